@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/peteski22/plugins-demo/internal/pluginhost"
+)
+
+const (
+	statusPass = "pass"
+	statusFail = "fail"
+	statusSkip = "skip"
+)
+
+// errSkip marks a check that doesn't apply to the plugin under test.
+var errSkip = errors.New("skipped")
+
+// result is the outcome of a single check.
+type result struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// suite holds the plugin under test and the state shared between checks.
+type suite struct {
+	proc        *pluginhost.Process
+	config      *pluginv1.PluginConfig
+	request     *pluginv1.HTTPRequest
+	response    *pluginv1.HTTPResponse
+	callTimeout time.Duration
+	stopTimeout time.Duration
+
+	metadata *pluginv1.Metadata
+	flows    []pluginv1.Flow
+}
+
+// check is a named conformance check. Returning errSkip (possibly wrapped) marks it skipped.
+type check struct {
+	name string
+	run  func(ctx context.Context, s *suite) (string, error)
+}
+
+// checks run in order; later checks rely on the state established by earlier ones.
+var checks = []check{
+	{"metadata", checkMetadata},
+	{"capabilities", checkCapabilities},
+	{"not-ready-before-configure", checkNotReadyBeforeConfigure},
+	{"configure", checkConfigure},
+	{"health-ready", checkHealthReady},
+	{"configure-idempotent", checkConfigureIdempotent},
+	{"request-round-trip", checkRequestRoundTrip},
+	{"response-round-trip", checkResponseRoundTrip},
+	{"stop", checkStop},
+	{"unhealthy-after-stop", checkUnhealthyAfterStop},
+	{"graceful-exit", checkGracefulExit},
+}
+
+// run executes every check and returns the results.
+func (s *suite) run(ctx context.Context) []result {
+	results := make([]result, 0, len(checks))
+	for _, c := range checks {
+		msg, err := c.run(ctx, s)
+
+		r := result{Name: c.name, Status: statusPass, Message: msg}
+		switch {
+		case errors.Is(err, errSkip):
+			r.Status = statusSkip
+			r.Message = err.Error()
+		case err != nil:
+			r.Status = statusFail
+			r.Message = err.Error()
+		}
+
+		results = append(results, r)
+	}
+
+	return results
+}
+
+// call bounds a single RPC by the per-call timeout.
+func (s *suite) call(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, s.callTimeout)
+}
+
+func checkMetadata(ctx context.Context, s *suite) (string, error) {
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	md, err := s.proc.Client.GetMetadata(ctx, &emptypb.Empty{})
+	if err != nil {
+		return "", fmt.Errorf("GetMetadata failed: %w", err)
+	}
+
+	if md.GetName() == "" {
+		return "", errors.New("metadata name is empty")
+	}
+
+	if md.GetVersion() == "" {
+		return "", errors.New("metadata version is empty")
+	}
+
+	s.metadata = md
+
+	return fmt.Sprintf("%s %s", md.GetName(), md.GetVersion()), nil
+}
+
+func checkCapabilities(ctx context.Context, s *suite) (string, error) {
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	caps, err := s.proc.Client.GetCapabilities(ctx, &emptypb.Empty{})
+	if err != nil {
+		return "", fmt.Errorf("GetCapabilities failed: %w", err)
+	}
+
+	flows := caps.GetFlows()
+	if len(flows) == 0 {
+		return "", errors.New("no flows declared")
+	}
+
+	// Record the flows before validating them so the round-trip checks still run.
+	s.flows = flows
+
+	seen := make(map[pluginv1.Flow]bool, len(flows))
+	for _, f := range flows {
+		if _, known := pluginv1.Flow_name[int32(f)]; !known {
+			return "", fmt.Errorf("unknown flow %d", f)
+		}
+
+		if seen[f] {
+			return "", fmt.Errorf("flow %s declared more than once", f)
+		}
+		seen[f] = true
+	}
+
+	return fmt.Sprintf("flows: %v", flows), nil
+}
+
+// checkNotReadyBeforeConfigure checks that a plugin doesn't claim to be ready before it has its configuration,
+// since the host would otherwise route traffic to it too early.
+func checkNotReadyBeforeConfigure(ctx context.Context, s *suite) (string, error) {
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.CheckReady(ctx, &emptypb.Empty{}); err == nil {
+		return "", errors.New("CheckReady succeeded before Configure")
+	}
+
+	return "", nil
+}
+
+func checkConfigure(ctx context.Context, s *suite) (string, error) {
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.Configure(ctx, s.config); err != nil {
+		return "", fmt.Errorf("Configure failed: %w", err)
+	}
+
+	return fmt.Sprintf("%d custom config keys", len(s.config.GetCustomConfig())), nil
+}
+
+func checkHealthReady(ctx context.Context, s *suite) (string, error) {
+	if err := s.healthy(ctx); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
+
+func checkConfigureIdempotent(ctx context.Context, s *suite) (string, error) {
+	callCtx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.Configure(callCtx, s.config); err != nil {
+		return "", fmt.Errorf("second Configure failed: %w", err)
+	}
+
+	if err := s.healthy(ctx); err != nil {
+		return "", fmt.Errorf("after second Configure: %w", err)
+	}
+
+	callCtx, cancel = s.call(ctx)
+	defer cancel()
+
+	md, err := s.proc.Client.GetMetadata(callCtx, &emptypb.Empty{})
+	if err != nil {
+		return "", fmt.Errorf("GetMetadata failed: %w", err)
+	}
+
+	if s.metadata != nil && !proto.Equal(md, s.metadata) {
+		return "", errors.New("metadata changed after reconfiguring")
+	}
+
+	return "", nil
+}
+
+func checkRequestRoundTrip(ctx context.Context, s *suite) (string, error) {
+	if !slices.Contains(s.flows, pluginv1.FlowRequest) {
+		return "", fmt.Errorf("%w: request flow not declared", errSkip)
+	}
+
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	resp, err := s.proc.Client.HandleRequest(ctx, proto.Clone(s.request).(*pluginv1.HTTPRequest))
+	if err != nil {
+		return "", fmt.Errorf("HandleRequest failed: %w", err)
+	}
+
+	if resp == nil {
+		return "", errors.New("HandleRequest returned no response")
+	}
+
+	if !resp.GetContinue() {
+		if !validStatus(resp.GetStatusCode()) {
+			return "", fmt.Errorf("short-circuit response has invalid status code %d", resp.GetStatusCode())
+		}
+
+		return fmt.Sprintf("short-circuited with %d", resp.GetStatusCode()), nil
+	}
+
+	if mr := resp.GetModifiedRequest(); mr != nil {
+		if mr.GetMethod() == "" || mr.GetPath() == "" {
+			return "", errors.New("modified request is missing method or path")
+		}
+
+		return "continued with modified request", nil
+	}
+
+	return "continued", nil
+}
+
+func checkResponseRoundTrip(ctx context.Context, s *suite) (string, error) {
+	if !slices.Contains(s.flows, pluginv1.FlowResponse) {
+		return "", fmt.Errorf("%w: response flow not declared", errSkip)
+	}
+
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	resp, err := s.proc.Client.HandleResponse(ctx, proto.Clone(s.response).(*pluginv1.HTTPResponse))
+	if err != nil {
+		return "", fmt.Errorf("HandleResponse failed: %w", err)
+	}
+
+	if resp == nil {
+		return "", errors.New("HandleResponse returned no response")
+	}
+
+	if !validStatus(resp.GetStatusCode()) {
+		return "", fmt.Errorf("response has invalid status code %d", resp.GetStatusCode())
+	}
+
+	return fmt.Sprintf("status %d, continue %t", resp.GetStatusCode(), resp.GetContinue()), nil
+}
+
+func checkStop(ctx context.Context, s *suite) (string, error) {
+	ctx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.Stop(ctx, &emptypb.Empty{}); err != nil {
+		return "", fmt.Errorf("Stop failed: %w", err)
+	}
+
+	return "", nil
+}
+
+// checkUnhealthyAfterStop checks that health and readiness drop once the plugin has stopped.
+func checkUnhealthyAfterStop(ctx context.Context, s *suite) (string, error) {
+	callCtx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.CheckHealth(callCtx, &emptypb.Empty{}); err == nil {
+		return "", errors.New("CheckHealth succeeded after Stop")
+	}
+
+	callCtx, cancel = s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.CheckReady(callCtx, &emptypb.Empty{}); err == nil {
+		return "", errors.New("CheckReady succeeded after Stop")
+	}
+
+	return "", nil
+}
+
+func checkGracefulExit(_ context.Context, s *suite) (string, error) {
+	start := time.Now()
+
+	exited, err := s.proc.Close(s.stopTimeout)
+	if !exited {
+		return "", fmt.Errorf("plugin did not exit within %v of SIGTERM and was killed", s.stopTimeout)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("plugin exited uncleanly: %w", err)
+	}
+
+	return fmt.Sprintf("exited in %v", time.Since(start).Round(time.Millisecond)), nil
+}
+
+// healthy checks that the plugin reports both healthy and ready.
+func (s *suite) healthy(ctx context.Context) error {
+	callCtx, cancel := s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.CheckHealth(callCtx, &emptypb.Empty{}); err != nil {
+		return fmt.Errorf("CheckHealth failed: %w", err)
+	}
+
+	callCtx, cancel = s.call(ctx)
+	defer cancel()
+
+	if _, err := s.proc.Client.CheckReady(callCtx, &emptypb.Empty{}); err != nil {
+		return fmt.Errorf("CheckReady failed: %w", err)
+	}
+
+	return nil
+}
+
+func validStatus(code int32) bool {
+	return code >= 100 && code <= 599
+}
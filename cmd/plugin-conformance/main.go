@@ -0,0 +1,158 @@
+// Command plugin-conformance launches a plugin binary the way mcpd does and checks that it
+// implements the plugin protocol correctly.
+//
+// Usage:
+//
+//	plugin-conformance [flags] <plugin-command> [args...]
+//
+// The plugin is started with --address and --network appended to its arguments, so non-Go plugins
+// can be tested too, e.g. "plugin-conformance -- uv run python main.py".
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/peteski22/plugins-demo/internal/pluginhost"
+)
+
+// report is the JSON form of a conformance run.
+type report struct {
+	Command []string `json:"command"`
+	Passed  bool     `json:"passed"`
+	Checks  []result `json:"checks"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("plugin-conformance: ")
+
+	var (
+		configPath   = flag.String("config", "", "JSON file of custom config passed to Configure")
+		requestPath  = flag.String("request", "", "HTTPRequest fixture in protobuf JSON (default: an MCP tools/call)")
+		responsePath = flag.String("response", "", "HTTPResponse fixture in protobuf JSON (default: a 200 JSON reply)")
+		network      = flag.String("network", "unix", "Network to serve the plugin on: unix or tcp")
+		address      = flag.String("address", "", "Address to serve the plugin on (default: temporary socket or free port)")
+		format       = flag.String("format", "text", "Report format: text or json")
+		verbose      = flag.Bool("v", false, "Show plugin output")
+		callTimeout  = flag.Duration("timeout", 5*time.Second, "Timeout for each RPC")
+		startTimeout = flag.Duration("start-timeout", pluginhost.DefaultStartTimeout, "Time allowed for startup")
+		stopTimeout  = flag.Duration("stop-timeout", pluginhost.DefaultStopTimeout, "Time allowed to exit after SIGTERM")
+	)
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <plugin-command> [args...]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format != "text" && *format != "json" {
+		log.Fatalf("invalid -format %q: must be text or json", *format)
+	}
+
+	cfg, err := pluginhost.ReadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	req, err := pluginhost.ReadRequest(*requestPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := pluginhost.ReadResponse(*responsePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var output io.Writer = io.Discard
+	if *verbose {
+		output = os.Stderr
+	}
+
+	ctx := context.Background()
+	command := flag.Args()
+
+	proc, err := pluginhost.Launch(ctx, command, pluginhost.Options{
+		Network:      *network,
+		Address:      *address,
+		StartTimeout: *startTimeout,
+		Output:       output,
+	})
+	if err != nil {
+		log.Fatalf("failed to launch plugin: %v", err)
+	}
+
+	s := &suite{
+		proc:        proc,
+		config:      cfg,
+		request:     req,
+		response:    resp,
+		callTimeout: *callTimeout,
+		stopTimeout: *stopTimeout,
+	}
+
+	rep := report{Command: command, Passed: true, Checks: s.run(ctx)}
+	for _, r := range rep.Checks {
+		if r.Status == statusFail {
+			rep.Passed = false
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rep); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		printText(os.Stdout, rep)
+	}
+
+	if !rep.Passed {
+		os.Exit(1)
+	}
+}
+
+func printText(w io.Writer, rep report) {
+	counts := map[string]int{}
+	for _, r := range rep.Checks {
+		counts[r.Status]++
+
+		if r.Message == "" {
+			_, _ = fmt.Fprintf(w, "%-4s  %s\n", labels[r.Status], r.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%-4s  %-22s  %s\n", labels[r.Status], r.Name, r.Message)
+	}
+
+	verdict := "PASSED"
+	if !rep.Passed {
+		verdict = "FAILED"
+	}
+
+	_, _ = fmt.Fprintf(
+		w,
+		"\n%s: %d passed, %d failed, %d skipped\n",
+		verdict,
+		counts[statusPass],
+		counts[statusFail],
+		counts[statusSkip],
+	)
+}
+
+var labels = map[string]string{
+	statusPass: "PASS",
+	statusFail: "FAIL",
+	statusSkip: "SKIP",
+}
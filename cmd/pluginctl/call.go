@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/peteski22/plugins-demo/internal/pluginhost"
+)
+
+// headerFlags collects repeated -header "Name: value" flags.
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(v string) error {
+	name, value, ok := strings.Cut(v, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("invalid header %q: expected \"Name: value\"", v)
+	}
+
+	h[strings.TrimSpace(name)] = strings.TrimSpace(value)
+
+	return nil
+}
+
+const callUsage = `Usage: pluginctl call [flags] <rpc>
+
+RPCs: metadata, capabilities, health, ready, configure, request, response, stop
+
+Request and response messages default to a representative MCP call and reply. Use -file to
+load one in protobuf JSON form, and -method/-path/-header/-body/-status to override fields.
+`
+
+func runCall(args []string) error {
+	headers := headerFlags{}
+
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	var (
+		network    = fs.String("network", "unix", "Network the plugin is serving on: unix or tcp")
+		address    = fs.String("address", "", "Address the plugin is serving on (required)")
+		configPath = fs.String("config", "", "JSON file of custom config for configure")
+		file       = fs.String("file", "", "HTTPRequest/HTTPResponse in protobuf JSON for request/response")
+		method     = fs.String("method", "", "Override the request method")
+		path       = fs.String("path", "", "Override the request path and query, keeping the URL and request URI in step")
+		body       = fs.String("body", "", "Override the message body (prefix with @ to read a file)")
+		status     = fs.Int("status", 0, "Override the response status code")
+		timeout    = fs.Duration("timeout", 5*time.Second, "RPC timeout")
+	)
+	fs.Var(headers, "header", "Set a header as \"Name: value\" (repeatable)")
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(fs.Output(), callUsage+"\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one rpc is required")
+	}
+
+	if *address == "" {
+		return errors.New("-address is required")
+	}
+
+	bodyBytes, err := readBody(*body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	conn, err := pluginhost.Dial(ctx, *network, *address, *timeout, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	client := pluginv1.NewPluginClient(conn)
+
+	var out proto.Message
+	switch rpc := fs.Arg(0); rpc {
+	case "metadata":
+		out, err = client.GetMetadata(ctx, &emptypb.Empty{})
+	case "capabilities":
+		out, err = client.GetCapabilities(ctx, &emptypb.Empty{})
+	case "health":
+		out, err = client.CheckHealth(ctx, &emptypb.Empty{})
+	case "ready":
+		out, err = client.CheckReady(ctx, &emptypb.Empty{})
+	case "stop":
+		out, err = client.Stop(ctx, &emptypb.Empty{})
+	case "configure":
+		cfg, cfgErr := pluginhost.ReadConfig(*configPath)
+		if cfgErr != nil {
+			return cfgErr
+		}
+		out, err = client.Configure(ctx, cfg)
+	case "request":
+		req, reqErr := pluginhost.ReadRequest(*file)
+		if reqErr != nil {
+			return reqErr
+		}
+		req.Method = valueOrDefault(*method, req.Method)
+		if *path != "" {
+			if err := setRequestTarget(req, *path); err != nil {
+				return err
+			}
+		}
+		req.Headers = mergeHeaders(req.Headers, headers)
+		if bodyBytes != nil {
+			req.Body = bodyBytes
+		}
+		out, err = client.HandleRequest(ctx, req)
+	case "response":
+		resp, respErr := pluginhost.ReadResponse(*file)
+		if respErr != nil {
+			return respErr
+		}
+		if *status != 0 {
+			resp.StatusCode = int32(*status)
+		}
+		resp.Headers = mergeHeaders(resp.Headers, headers)
+		if bodyBytes != nil {
+			resp.Body = bodyBytes
+		}
+		out, err = client.HandleResponse(ctx, resp)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown rpc %q", rpc)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", fs.Arg(0), err)
+	}
+
+	return printMessage(out)
+}
+
+// printMessage writes the message as protobuf JSON, followed by any textual body in readable form.
+func printMessage(msg proto.Message) error {
+	data, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: false}.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode result: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	var body []byte
+	if resp, ok := msg.(*pluginv1.HTTPResponse); ok {
+		body = resp.GetBody()
+		if mr := resp.GetModifiedRequest(); len(body) == 0 && mr != nil {
+			body = mr.GetBody()
+		}
+	}
+
+	if len(body) > 0 && utf8.Valid(body) {
+		fmt.Printf("--- body ---\n%s\n", body)
+	}
+
+	return nil
+}
+
+func readBody(v string) ([]byte, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	if name, ok := strings.CutPrefix(v, "@"); ok {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %w", err)
+		}
+
+		return data, nil
+	}
+
+	return []byte(v), nil
+}
+
+// setRequestTarget points the request at target, a path with an optional query. Path, RequestUri and Url
+// all describe the target, so they're rewritten together; the URL keeps its scheme and host.
+func setRequestTarget(req *pluginv1.HTTPRequest, target string) error {
+	u, err := url.ParseRequestURI(target)
+	if err != nil || u.Host != "" || !strings.HasPrefix(target, "/") {
+		return fmt.Errorf("invalid -path %q: must be an absolute path, optionally with a query", target)
+	}
+
+	full, err := url.Parse(req.Url)
+	if err != nil || full.Host == "" {
+		full = &url.URL{Scheme: "http", Host: "localhost:8090"}
+	}
+	full.Path, full.RawPath, full.RawQuery = u.Path, u.RawPath, u.RawQuery
+
+	req.Path = u.Path
+	req.RequestUri = target
+	req.Url = full.String()
+
+	return nil
+}
+
+// mergeHeaders applies overrides on top of base, replacing any case variant of the same name.
+func mergeHeaders(base map[string]string, overrides headerFlags) map[string]string {
+	if base == nil {
+		base = make(map[string]string)
+	}
+
+	for name, value := range overrides {
+		for k := range base {
+			if strings.EqualFold(k, name) {
+				delete(base, k)
+			}
+		}
+		base[name] = value
+	}
+
+	return base
+}
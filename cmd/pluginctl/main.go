@@ -0,0 +1,67 @@
+// Command pluginctl helps develop mcpd plugins without running the whole host.
+//
+// Usage:
+//
+//	pluginctl scaffold [flags] <name>                  generate a Go plugin skeleton
+//	pluginctl run [flags] <plugin-command> [args...]   start a plugin standalone
+//	pluginctl call [flags] <rpc>                       send an RPC to a running plugin
+//	pluginctl replay [flags] <recording> <plugin-command> [args...]
+//	                                                   replay recorded traffic through a plugin
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+const usage = `pluginctl helps develop mcpd plugins without running the whole host.
+
+Usage:
+  pluginctl scaffold [flags] <name>                  Generate a Go plugin skeleton
+  pluginctl run [flags] <plugin-command> [args...]   Start a plugin standalone
+  pluginctl call [flags] <rpc>                       Send an RPC to a running plugin
+  pluginctl replay [flags] <recording> <plugin-command> [args...]
+                                                     Replay recorded traffic through a plugin
+
+Run "pluginctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("pluginctl: ")
+
+	if len(os.Args) < 2 {
+		_, _ = fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "scaffold":
+		err = runScaffold(args)
+	case "run":
+		err = runRun(args)
+	case "call":
+		err = runCall(args)
+	case "replay":
+		err = runReplay(args)
+	case "help", "-h", "--help":
+		_, _ = fmt.Fprint(os.Stdout, usage)
+	default:
+		_, _ = fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
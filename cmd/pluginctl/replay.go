@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/peteski22/plugins-demo/internal/pluginhost"
+)
+
+const replayUsage = `Usage: pluginctl replay [flags] <recording.jsonl> <plugin-command> [args...]
+
+Each line of the recording is a JSON object with an optional "id" and a "request" and/or
+"response" in protobuf JSON form. The plugin is launched and configured once, then every
+message is sent to it in order and the plugin's decision is printed.
+`
+
+// maxReasonBytes caps how much of a rejection body is shown on the summary line.
+const maxReasonBytes = 200
+
+// record is one line of a replay recording.
+type record struct {
+	ID       string          `json:"id"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response"`
+}
+
+// replayCounts tallies the decisions seen during a replay.
+type replayCounts struct {
+	continued, modified, rejected, failed int
+}
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	var (
+		network      = fs.String("network", "unix", "Network to serve the plugin on: unix or tcp")
+		address      = fs.String("address", "", "Address to serve the plugin on (default: temporary socket or free port)")
+		configPath   = fs.String("config", "", "JSON file of custom config passed to Configure")
+		verbose      = fs.Bool("v", false, "Print every plugin reply in full and show plugin output")
+		callTimeout  = fs.Duration("timeout", 5*time.Second, "Timeout for each RPC")
+		startTimeout = fs.Duration("start-timeout", pluginhost.DefaultStartTimeout, "Time allowed for startup")
+		stopTimeout  = fs.Duration("stop-timeout", pluginhost.DefaultStopTimeout, "Time allowed to exit after SIGTERM")
+	)
+	fs.Usage = func() {
+		_, _ = fmt.Fprint(fs.Output(), replayUsage+"\n")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("a recording and a plugin command are required")
+	}
+
+	records, err := readRecording(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := pluginhost.ReadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = io.Discard
+	if *verbose {
+		output = os.Stderr
+	}
+
+	ctx := context.Background()
+
+	proc, err := pluginhost.Launch(ctx, fs.Args()[1:], pluginhost.Options{
+		Network:      *network,
+		Address:      *address,
+		StartTimeout: *startTimeout,
+		Output:       output,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _, _ = proc.Close(*stopTimeout) }()
+
+	configureCtx, cancel := context.WithTimeout(ctx, *callTimeout)
+	_, err = proc.Client.Configure(configureCtx, cfg)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("Configure failed: %w", err)
+	}
+
+	var counts replayCounts
+	for i, rec := range records {
+		id := valueOrDefault(rec.ID, strconv.Itoa(i+1))
+
+		if rec.Request != nil {
+			replayRequest(ctx, proc.Client, id, rec.Request, *callTimeout, *verbose, &counts)
+		}
+
+		if rec.Response != nil {
+			replayResponse(ctx, proc.Client, id, rec.Response, *callTimeout, *verbose, &counts)
+		}
+	}
+
+	fmt.Printf(
+		"\n%d messages from %d records: %d continued, %d modified, %d rejected, %d failed\n",
+		counts.continued+counts.modified+counts.rejected+counts.failed,
+		len(records),
+		counts.continued,
+		counts.modified,
+		counts.rejected,
+		counts.failed,
+	)
+
+	stopCtx, cancel := context.WithTimeout(ctx, *stopTimeout)
+	defer cancel()
+
+	if _, err := proc.Client.Stop(stopCtx, &emptypb.Empty{}); err != nil {
+		log.Printf("Stop failed: %v", err)
+	}
+
+	if counts.failed > 0 {
+		return fmt.Errorf("%d messages could not be replayed", counts.failed)
+	}
+
+	return nil
+}
+
+func replayRequest(
+	ctx context.Context,
+	client pluginv1.PluginClient,
+	id string,
+	raw json.RawMessage,
+	timeout time.Duration,
+	verbose bool,
+	counts *replayCounts,
+) {
+	req := &pluginv1.HTTPRequest{}
+	if err := protojson.Unmarshal(raw, req); err != nil {
+		counts.failed++
+		fmt.Printf("%-8s request   invalid: %v\n", id, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := client.HandleRequest(ctx, req)
+	if err != nil {
+		counts.failed++
+		fmt.Printf("%-8s request   error: %v\n", id, err)
+		return
+	}
+
+	switch {
+	case !out.GetContinue():
+		counts.rejected++
+		fmt.Printf("%-8s request   rejected %d  %s\n", id, out.GetStatusCode(), reason(out.GetBody()))
+	case out.GetModifiedRequest() != nil:
+		counts.modified++
+		fmt.Printf("%-8s request   modified\n", id)
+	default:
+		counts.continued++
+		fmt.Printf("%-8s request   continued\n", id)
+	}
+
+	if verbose {
+		_ = printMessage(out)
+	}
+}
+
+func replayResponse(
+	ctx context.Context,
+	client pluginv1.PluginClient,
+	id string,
+	raw json.RawMessage,
+	timeout time.Duration,
+	verbose bool,
+	counts *replayCounts,
+) {
+	resp := &pluginv1.HTTPResponse{}
+	if err := protojson.Unmarshal(raw, resp); err != nil {
+		counts.failed++
+		fmt.Printf("%-8s response  invalid: %v\n", id, err)
+		return
+	}
+
+	// Recordings may omit continue; the host always sends it set on the response flow.
+	resp.Continue = true
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := client.HandleResponse(ctx, resp)
+	if err != nil {
+		counts.failed++
+		fmt.Printf("%-8s response  error: %v\n", id, err)
+		return
+	}
+
+	switch {
+	case !out.GetContinue():
+		counts.rejected++
+		fmt.Printf("%-8s response  rejected %d  %s\n", id, out.GetStatusCode(), reason(out.GetBody()))
+	case !proto.Equal(out, resp):
+		counts.modified++
+		fmt.Printf("%-8s response  modified (status %d)\n", id, out.GetStatusCode())
+	default:
+		counts.continued++
+		fmt.Printf("%-8s response  continued\n", id)
+	}
+
+	if verbose {
+		_ = printMessage(out)
+	}
+}
+
+// readRecording parses a JSONL recording, skipping blank lines.
+func readRecording(path string) ([]record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var records []record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid record: %w", path, line, err)
+		}
+
+		// A literal null is treated like a missing field.
+		if string(rec.Request) == "null" {
+			rec.Request = nil
+		}
+		if string(rec.Response) == "null" {
+			rec.Response = nil
+		}
+
+		if rec.Request == nil && rec.Response == nil {
+			return nil, fmt.Errorf("%s:%d: record has neither request nor response", path, line)
+		}
+
+		records = append(records, rec)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	return records, nil
+}
+
+// reason renders a rejection body for the summary line.
+func reason(body []byte) string {
+	if !utf8.Valid(body) {
+		return fmt.Sprintf("(%d bytes)", len(body))
+	}
+
+	if len(body) > maxReasonBytes {
+		return string(body[:maxReasonBytes]) + "..."
+	}
+
+	return string(body)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/peteski22/plugins-demo/internal/pluginhost"
+)
+
+func runRun(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	var (
+		network     = fs.String("network", "unix", "Network to serve the plugin on: unix or tcp")
+		address     = fs.String("address", "", "Address to serve the plugin on (default: temporary socket or free port)")
+		configPath  = fs.String("config", "", "JSON file of custom config; when set, Configure is called after start")
+		stopTimeout = fs.Duration("stop-timeout", pluginhost.DefaultStopTimeout, "Time allowed to exit after SIGTERM")
+	)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: pluginctl run [flags] <plugin-command> [args...]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("plugin command is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	proc, err := pluginhost.Launch(ctx, fs.Args(), pluginhost.Options{
+		Network: *network,
+		Address: *address,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("plugin serving on %s %s", proc.Network, proc.Address)
+	log.Printf("  pluginctl call -network %s -address %s metadata", proc.Network, proc.Address)
+
+	if *configPath != "" {
+		if err := configure(ctx, proc, *configPath); err != nil {
+			_, _ = proc.Close(*stopTimeout)
+			return err
+		}
+		log.Printf("plugin configured from %s and ready", *configPath)
+	}
+
+	select {
+	case <-ctx.Done():
+		log.Println("stopping plugin...")
+	case <-proc.Done():
+		return fmt.Errorf("plugin exited: %v", proc.Err())
+	}
+
+	// Mirror the host's shutdown: Stop first, then SIGTERM.
+	stopCtx, cancel := context.WithTimeout(context.Background(), *stopTimeout)
+	defer cancel()
+
+	if _, err := proc.Client.Stop(stopCtx, &emptypb.Empty{}); err != nil {
+		log.Printf("Stop failed: %v", err)
+	}
+
+	exited, err := proc.Close(*stopTimeout)
+	if !exited {
+		return fmt.Errorf("plugin did not exit within %v and was killed", *stopTimeout)
+	}
+
+	return err
+}
+
+func configure(ctx context.Context, proc *pluginhost.Process, configPath string) error {
+	cfg, err := pluginhost.ReadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if _, err := proc.Client.Configure(ctx, cfg); err != nil {
+		return fmt.Errorf("Configure failed: %w", err)
+	}
+
+	if _, err := proc.Client.CheckReady(ctx, &emptypb.Empty{}); err != nil {
+		return fmt.Errorf("plugin not ready after Configure: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+const defaultModulePrefix = "github.com/peteski22/plugins-demo/sample-plugins/"
+
+var pluginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// scaffoldData is the data the plugin templates are rendered with.
+type scaffoldData struct {
+	Name        string
+	Module      string
+	Description string
+	Type        string
+	Title       string
+	Label       string
+	FlowList    string
+	Request     bool
+	Response    bool
+}
+
+func runScaffold(args []string) error {
+	fs := flag.NewFlagSet("scaffold", flag.ExitOnError)
+	var (
+		dir         = fs.String("dir", "", "Output directory (default: sample-plugins/<name>)")
+		module      = fs.String("module", "", "Go module path (default: "+defaultModulePrefix+"<name>)")
+		description = fs.String("description", "", "Plugin description for GetMetadata")
+		flows       = fs.String("flows", "request", "Comma-separated flows to handle: request, response")
+		force       = fs.Bool("force", false, "Overwrite existing files")
+	)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintln(fs.Output(), "Usage: pluginctl scaffold [flags] <name>")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("plugin name is required")
+	}
+
+	name := fs.Arg(0)
+	if !pluginNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid plugin name %q: use lowercase letters, digits and dashes", name)
+	}
+
+	data := scaffoldData{
+		Name:        name,
+		Module:      valueOrDefault(*module, defaultModulePrefix+name),
+		Description: valueOrDefault(*description, "TODO: describe "+name),
+		Type:        typeName(name) + "Plugin",
+		Title:       titleName(name),
+		Label:       strings.ReplaceAll(name, "-", " "),
+	}
+
+	var flowList []string
+	for _, f := range strings.Split(*flows, ",") {
+		switch strings.ToLower(strings.TrimSpace(f)) {
+		case "request":
+			data.Request = true
+		case "response":
+			data.Response = true
+		default:
+			return fmt.Errorf("invalid flow %q: must be request or response", f)
+		}
+	}
+	if data.Request {
+		flowList = append(flowList, "pluginv1.FlowRequest")
+	}
+	if data.Response {
+		flowList = append(flowList, "pluginv1.FlowResponse")
+	}
+	data.FlowList = strings.Join(flowList, ", ")
+
+	if strings.ContainsAny(data.Description, "\"\\\n") {
+		return errors.New("description must not contain quotes, backslashes or newlines")
+	}
+
+	outDir := valueOrDefault(*dir, filepath.Join("sample-plugins", name))
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+
+	files := []struct {
+		template string
+		output   string
+		gofmt    bool
+	}{
+		{"templates/main.go.tmpl", "main.go", true},
+		{"templates/go.mod.tmpl", "go.mod", false},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(outDir, f.output)
+		if _, err := os.Stat(path); err == nil && !*force {
+			return fmt.Errorf("%s already exists (use -force to overwrite)", path)
+		}
+
+		content, err := render(f.template, data, f.gofmt)
+		if err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		fmt.Printf("created %s\n", path)
+	}
+
+	fmt.Printf("\nNext steps:\n  cd %s\n  go mod tidy\n  go build -o %s-plugin .\n", outDir, name)
+
+	return nil
+}
+
+func render(name string, data scaffoldData, gofmt bool) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+
+	if !gofmt {
+		return buf.Bytes(), nil
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go for %s: %w", name, err)
+	}
+
+	return formatted, nil
+}
+
+// typeName converts "my-plugin" to "MyPlugin".
+func typeName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
+
+// titleName converts "my-plugin" to "My plugin" for log messages.
+func titleName(name string) string {
+	label := strings.ReplaceAll(name, "-", " ")
+
+	return strings.ToUpper(label[:1]) + label[1:]
+}
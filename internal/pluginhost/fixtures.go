@@ -0,0 +1,100 @@
+package pluginhost
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultRequest is a representative MCP tools/call request.
+func DefaultRequest() *pluginv1.HTTPRequest {
+	return &pluginv1.HTTPRequest{
+		Method:     "POST",
+		Url:        "http://localhost:8090/mcp",
+		Path:       "/mcp",
+		RequestUri: "/mcp",
+		RemoteAddr: "127.0.0.1:54321",
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"User-Agent":   "mcpd-plugin-tools",
+		},
+		Body: []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`),
+	}
+}
+
+// DefaultResponse is a representative successful MCP response.
+func DefaultResponse() *pluginv1.HTTPResponse {
+	return &pluginv1.HTTPResponse{
+		StatusCode: 200,
+		Continue:   true,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: []byte(`{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"hi"}]}}`),
+	}
+}
+
+// ReadConfig loads a plugin config from a JSON object of custom config keys.
+// An empty path yields an empty config.
+func ReadConfig(path string) (*pluginv1.PluginConfig, error) {
+	cfg := &pluginv1.PluginConfig{CustomConfig: map[string]string{}}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg.CustomConfig); err != nil {
+		return nil, fmt.Errorf("invalid config %s: expected a JSON object of strings: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ReadRequest loads an HTTPRequest in protobuf JSON form, or returns DefaultRequest for an empty path.
+func ReadRequest(path string) (*pluginv1.HTTPRequest, error) {
+	if path == "" {
+		return DefaultRequest(), nil
+	}
+
+	req := &pluginv1.HTTPRequest{}
+	if err := readProtoJSON(path, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// ReadResponse loads an HTTPResponse in protobuf JSON form, or returns DefaultResponse for an empty path.
+func ReadResponse(path string) (*pluginv1.HTTPResponse, error) {
+	if path == "" {
+		return DefaultResponse(), nil
+	}
+
+	resp := &pluginv1.HTTPResponse{}
+	if err := readProtoJSON(path, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func readProtoJSON(path string, msg proto.Message) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := protojson.Unmarshal(data, msg); err != nil {
+		return fmt.Errorf("invalid %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+// Package pluginhost launches plugin binaries the way mcpd does and connects to them over gRPC.
+package pluginhost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// DefaultStartTimeout is how long Launch waits for the plugin to accept connections.
+	DefaultStartTimeout = 10 * time.Second
+
+	// DefaultStopTimeout is how long Close waits after SIGTERM before killing the plugin.
+	DefaultStopTimeout = 5 * time.Second
+)
+
+// Options controls how a plugin process is started.
+type Options struct {
+	// Network is "unix" (default) or "tcp".
+	Network string
+
+	// Address is the socket path or host:port to serve on.
+	// Defaults to a socket in a fresh temporary directory for unix, or a free loopback port for tcp.
+	Address string
+
+	// StartTimeout bounds how long to wait for the plugin to become reachable.
+	StartTimeout time.Duration
+
+	// Output receives the plugin's stdout and stderr. Defaults to os.Stderr.
+	Output io.Writer
+}
+
+// Process is a running plugin with an open client connection.
+type Process struct {
+	Client  pluginv1.PluginClient
+	Network string
+	Address string
+
+	cmd    *exec.Cmd
+	conn   *grpc.ClientConn
+	done   chan struct{}
+	err    error
+	tmpDir string
+}
+
+// Launch starts command with --address and --network appended, and connects once it's serving.
+func Launch(ctx context.Context, command []string, opts Options) (*Process, error) {
+	if len(command) == 0 {
+		return nil, errors.New("plugin command is required")
+	}
+
+	network := opts.Network
+	if network == "" {
+		network = "unix"
+	}
+
+	p := &Process{
+		Network: network,
+		Address: opts.Address,
+		done:    make(chan struct{}),
+	}
+
+	if p.Address == "" {
+		switch network {
+		case "unix":
+			dir, err := os.MkdirTemp("", "mcpd-plugin-")
+			if err != nil {
+				return nil, fmt.Errorf("failed to create socket directory: %w", err)
+			}
+			p.tmpDir = dir
+			p.Address = filepath.Join(dir, "plugin.sock")
+		case "tcp":
+			addr, err := freeTCPAddress()
+			if err != nil {
+				return nil, err
+			}
+			p.Address = addr
+		default:
+			return nil, fmt.Errorf("unsupported network %q", network)
+		}
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = os.Stderr
+	}
+
+	args := append(command[1:len(command):len(command)], "--address", p.Address, "--network", network)
+	p.cmd = exec.Command(command[0], args...)
+	p.cmd.Stdout = output
+	p.cmd.Stderr = output
+
+	if err := p.cmd.Start(); err != nil {
+		p.cleanup()
+		return nil, fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	go func() {
+		p.err = p.cmd.Wait()
+		close(p.done)
+	}()
+
+	startTimeout := opts.StartTimeout
+	if startTimeout <= 0 {
+		startTimeout = DefaultStartTimeout
+	}
+
+	conn, err := Dial(ctx, network, p.Address, startTimeout, p.done)
+	if err != nil {
+		_ = p.kill()
+		p.cleanup()
+		return nil, err
+	}
+
+	p.conn = conn
+	p.Client = pluginv1.NewPluginClient(conn)
+
+	return p, nil
+}
+
+// Dial connects to a plugin serving on network/address and waits until the connection is ready.
+// A non-nil exited channel aborts the wait early when the plugin process dies.
+func Dial(
+	ctx context.Context,
+	network string,
+	address string,
+	timeout time.Duration,
+	exited <-chan struct{},
+) (*grpc.ClientConn, error) {
+	target := address
+	if network == "unix" {
+		target = "unix://" + address
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s %s: %w", network, address, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The plugin may still be starting; keep retrying until ready, timed out or exited.
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return conn, nil
+		}
+
+		if state == connectivity.Idle || state == connectivity.TransientFailure {
+			conn.Connect()
+		}
+
+		select {
+		case <-exited:
+			_ = conn.Close()
+			return nil, errors.New("plugin exited before it started serving")
+		case <-ctx.Done():
+			_ = conn.Close()
+			return nil, fmt.Errorf("plugin not reachable on %s %s within %v", network, address, timeout)
+		case <-waitForChange(ctx, conn, state):
+		}
+	}
+}
+
+// waitForChange returns a channel that's closed when the connection leaves state, polling so a
+// connection stuck in backoff is retried promptly.
+func waitForChange(ctx context.Context, conn *grpc.ClientConn, state connectivity.State) <-chan struct{} {
+	ch := make(chan struct{})
+
+	go func() {
+		defer close(ch)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+
+		conn.WaitForStateChange(waitCtx, state)
+	}()
+
+	return ch
+}
+
+// Done is closed when the plugin process exits.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Err returns the process exit error once Done is closed.
+func (p *Process) Err() error {
+	<-p.done
+	return p.err
+}
+
+// Close disconnects, sends SIGTERM and waits up to timeout before killing the process.
+// It reports whether the plugin exited on its own.
+func (p *Process) Close(timeout time.Duration) (bool, error) {
+	defer p.cleanup()
+
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultStopTimeout
+	}
+
+	select {
+	case <-p.done:
+		return true, p.err
+	default:
+	}
+
+	// SIGTERM isn't deliverable on every platform (e.g. Windows); fall back to killing.
+	if err := p.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		select {
+		case <-p.done:
+			return true, p.err
+		default:
+		}
+
+		return false, p.kill()
+	}
+
+	select {
+	case <-p.done:
+		return true, p.err
+	case <-time.After(timeout):
+		return false, p.kill()
+	}
+}
+
+func (p *Process) kill() error {
+	if err := p.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill plugin: %w", err)
+	}
+
+	<-p.done
+
+	return nil
+}
+
+func (p *Process) cleanup() {
+	if p.tmpDir != "" {
+		_ = os.RemoveAll(p.tmpDir)
+	}
+}
+
+// freeTCPAddress asks the kernel for an unused loopback port.
+// The port is released before the plugin binds it, so another process could take it in between,
+// but that's far less likely than two plugins picking the same fixed or time-derived port.
+func freeTCPAddress() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate a TCP port: %w", err)
+	}
+
+	addr := l.Addr().String()
+	if err := l.Close(); err != nil {
+		return "", fmt.Errorf("failed to release TCP port %s: %w", addr, err)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const defaultRolesClaim = "roles"
+
+// jwtValidator verifies bearer JWTs against a shared HMAC secret or a JWKS endpoint.
+type jwtValidator struct {
+	parser     *jwt.Parser
+	hmacSecret []byte
+	jwks       *jwksCache
+	rolesClaim string
+}
+
+// newJWTValidator builds a validator from the jwt_* custom config keys.
+// It returns nil when JWT validation is not configured.
+func newJWTValidator(cfg map[string]string) (*jwtValidator, error) {
+	secret := cfg["jwt_hmac_secret"]
+	jwksURL := cfg["jwt_jwks_url"]
+
+	if secret == "" && jwksURL == "" {
+		return nil, nil
+	}
+
+	if secret != "" && jwksURL != "" {
+		return nil, fmt.Errorf("only one of jwt_hmac_secret or jwt_jwks_url may be set")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+
+	if iss := cfg["jwt_issuer"]; iss != "" {
+		opts = append(opts, jwt.WithIssuer(iss))
+	}
+
+	if aud := cfg["jwt_audience"]; aud != "" {
+		opts = append(opts, jwt.WithAudience(aud))
+	}
+
+	if leewayStr, exists := cfg["jwt_leeway"]; exists {
+		leeway, err := time.ParseDuration(leewayStr)
+		if err != nil || leeway < 0 {
+			return nil, fmt.Errorf("invalid jwt_leeway %q", leewayStr)
+		}
+		opts = append(opts, jwt.WithLeeway(leeway))
+	}
+
+	v := &jwtValidator{
+		rolesClaim: cfg["jwt_roles_claim"],
+	}
+
+	if v.rolesClaim == "" {
+		v.rolesClaim = defaultRolesClaim
+	}
+
+	// Pin the accepted algorithms to the key type to prevent algorithm confusion.
+	if secret != "" {
+		v.hmacSecret = []byte(secret)
+		opts = append(opts, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
+	} else {
+		var refresh time.Duration
+		if refreshStr, exists := cfg["jwt_jwks_refresh"]; exists {
+			r, err := time.ParseDuration(refreshStr)
+			if err != nil || r <= 0 {
+				return nil, fmt.Errorf("invalid jwt_jwks_refresh %q", refreshStr)
+			}
+			refresh = r
+		}
+
+		v.jwks = newJWKSCache(jwksURL, refresh)
+		opts = append(opts, jwt.WithValidMethods([]string{
+			"RS256", "RS384", "RS512",
+			"PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512",
+			"EdDSA",
+		}))
+	}
+
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// validate verifies the token and returns the identity carried in its claims.
+func (v *jwtValidator) validate(ctx context.Context, token string) (identity, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if v.hmacSecret != nil {
+			return v.hmacSecret, nil
+		}
+
+		kid, _ := t.Header["kid"].(string)
+		return v.jwks.key(ctx, kid)
+	})
+	if err != nil {
+		return identity{}, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return identity{}, fmt.Errorf("token has no subject")
+	}
+
+	return identity{
+		subject: subject,
+		roles:   rolesFromClaim(claims[v.rolesClaim]),
+	}, nil
+}
+
+// rolesFromClaim accepts either a JSON array of strings or a space-separated string (OAuth scope style).
+func rolesFromClaim(claim any) []string {
+	switch c := claim.(type) {
+	case string:
+		return strings.Fields(c)
+	case []any:
+		roles := make([]string, 0, len(c))
+		for _, r := range c {
+			if s, ok := r.(string); ok && s != "" {
+				roles = append(roles, s)
+			}
+		}
+		return roles
+	default:
+		return nil
+	}
+}
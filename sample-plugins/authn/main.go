@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultSubjectHeader = "X-Authenticated-Subject"
+	defaultRolesHeader   = "X-Authenticated-Roles"
+	defaultRealm         = "mcpd"
+)
+
+// identity is the authenticated caller passed on to downstream plugins.
+type identity struct {
+	subject string
+	roles   []string
+}
+
+// apiKeyConfig is the JSON form of an entry in the "api_keys" custom config value.
+type apiKeyConfig struct {
+	Key     string   `json:"key"`
+	Subject string   `json:"subject"`
+	Roles   []string `json:"roles"`
+}
+
+// AuthNPlugin authenticates requests using static API keys or JWTs.
+type AuthNPlugin struct {
+	pluginv1.BasePlugin
+
+	mu                 sync.RWMutex
+	apiKeys            map[[sha256.Size]byte]identity
+	jwt                *jwtValidator
+	subjectHeader      string
+	rolesHeader        string
+	realm              string
+	stripAuthorization bool
+	initialized        bool
+}
+
+func newAuthNPlugin() *AuthNPlugin {
+	return &AuthNPlugin{
+		subjectHeader: defaultSubjectHeader,
+		rolesHeader:   defaultRolesHeader,
+		realm:         defaultRealm,
+	}
+}
+
+func (p *AuthNPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "authn",
+		Version:     "1.0.0",
+		Description: "Authenticates requests using API keys or JWTs",
+	}, nil
+}
+
+func (p *AuthNPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *AuthNPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	apiKeys, err := parseAPIKeys(custom["api_keys"])
+	if err != nil {
+		return nil, fmt.Errorf("authn plugin configuration failed: %w", err)
+	}
+
+	validator, err := newJWTValidator(custom)
+	if err != nil {
+		return nil, fmt.Errorf("authn plugin configuration failed: %w", err)
+	}
+
+	if len(apiKeys) == 0 && validator == nil {
+		return nil, fmt.Errorf(
+			"authn plugin configuration failed: configure api_keys and/or jwt_hmac_secret or jwt_jwks_url",
+		)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.apiKeys = apiKeys
+	p.jwt = validator
+	p.subjectHeader = valueOrDefault(custom["subject_header"], defaultSubjectHeader)
+	p.rolesHeader = valueOrDefault(custom["roles_header"], defaultRolesHeader)
+	p.realm = valueOrDefault(custom["realm"], defaultRealm)
+	p.stripAuthorization = custom["strip_authorization"] == "true"
+	p.initialized = true
+
+	log.Printf("AuthN plugin initialized: %d API keys, JWT validation enabled: %t", len(apiKeys), validator != nil)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthNPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("AuthN plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.apiKeys = nil
+	p.jwt = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthNPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("authn plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthNPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("authn plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthNPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*pluginv1.HTTPResponse, error) {
+	log.Printf("AuthN handling request: %s %s", req.Method, req.Path)
+
+	// Validating a JWT can mean fetching the JWKS, so the lock is released first. Configure replaces the
+	// key map and validator rather than changing them, so the copies stay consistent.
+	p.mu.RLock()
+	initialized := p.initialized
+	apiKeys, validator := p.apiKeys, p.jwt
+	subjectHeader, rolesHeader := p.subjectHeader, p.rolesHeader
+	realm, stripAuthorization := p.realm, p.stripAuthorization
+	p.mu.RUnlock()
+
+	if !initialized {
+		return nil, fmt.Errorf("authn plugin not initialized")
+	}
+
+	scheme, credentials, found := strings.Cut(headerValue(req.Headers, "Authorization"), " ")
+	if !found || credentials == "" {
+		return unauthorized(realm, "", "missing credentials"), nil
+	}
+
+	id, err := authenticate(ctx, apiKeys, validator, scheme, strings.TrimSpace(credentials))
+	if err != nil {
+		log.Printf("AuthN rejected request %s %s: %v", req.Method, req.Path, err)
+		return unauthorized(realm, "invalid_token", "invalid credentials"), nil
+	}
+
+	// Drop any caller-supplied identity headers so they can't be spoofed past this plugin.
+	headers := make(map[string]string, len(req.Headers)+2)
+	for k, v := range req.Headers {
+		if strings.EqualFold(k, subjectHeader) || strings.EqualFold(k, rolesHeader) {
+			continue
+		}
+		if stripAuthorization && strings.EqualFold(k, "Authorization") {
+			continue
+		}
+		headers[k] = v
+	}
+
+	headers[subjectHeader] = id.subject
+	if len(id.roles) > 0 {
+		headers[rolesHeader] = strings.Join(id.roles, ",")
+	}
+
+	log.Printf("AuthN authenticated subject: %s", id.subject)
+
+	return &pluginv1.HTTPResponse{
+		Continue: true,
+		ModifiedRequest: &pluginv1.HTTPRequest{
+			Method:     req.Method,
+			Url:        req.Url,
+			Path:       req.Path,
+			Headers:    headers,
+			Body:       req.Body,
+			RemoteAddr: req.RemoteAddr,
+			RequestUri: req.RequestUri,
+		},
+	}, nil
+}
+
+// authenticate resolves credentials to an identity.
+// Bearer credentials are checked against API keys first, then validated as a JWT when a validator is set.
+func authenticate(
+	ctx context.Context,
+	apiKeys map[[sha256.Size]byte]identity,
+	validator *jwtValidator,
+	scheme string,
+	credentials string,
+) (identity, error) {
+	switch strings.ToLower(scheme) {
+	case "apikey":
+		if id, ok := apiKeys[sha256.Sum256([]byte(credentials))]; ok {
+			return id, nil
+		}
+		return identity{}, fmt.Errorf("unknown API key")
+	case "bearer":
+		if id, ok := apiKeys[sha256.Sum256([]byte(credentials))]; ok {
+			return id, nil
+		}
+		if validator == nil {
+			return identity{}, fmt.Errorf("unknown API key")
+		}
+		return validator.validate(ctx, credentials)
+	default:
+		return identity{}, fmt.Errorf("unsupported authorization scheme %q", scheme)
+	}
+}
+
+// unauthorized builds a 401 response with a WWW-Authenticate challenge.
+func unauthorized(realm string, errorCode string, description string) *pluginv1.HTTPResponse {
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+	if errorCode != "" {
+		challenge += fmt.Sprintf(", error=%q, error_description=%q", errorCode, description)
+	}
+
+	body, _ := json.Marshal(map[string]string{"error": "Unauthorized", "message": description})
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: 401,
+		Headers: map[string]string{
+			"Content-Type":     "application/json",
+			"WWW-Authenticate": challenge,
+		},
+		Body: body,
+	}
+}
+
+// parseAPIKeys decodes the api_keys JSON array and indexes identities by key digest,
+// so raw keys are not kept in memory after configuration.
+func parseAPIKeys(raw string) (map[[sha256.Size]byte]identity, error) {
+	keys := make(map[[sha256.Size]byte]identity)
+	if raw == "" {
+		return keys, nil
+	}
+
+	var cfgs []apiKeyConfig
+	if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid api_keys: %w", err)
+	}
+
+	for i, c := range cfgs {
+		if c.Key == "" || c.Subject == "" {
+			return nil, fmt.Errorf("api_keys entry %d: key and subject are required", i)
+		}
+
+		digest := sha256.Sum256([]byte(c.Key))
+		if _, exists := keys[digest]; exists {
+			return nil, fmt.Errorf("api_keys entry %d: duplicate key", i)
+		}
+
+		keys[digest] = identity{subject: c.Subject, roles: c.Roles}
+	}
+
+	return keys, nil
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newAuthNPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/peteski22/plugins-demo/sample-plugins/authz
+
+go 1.25.1
+
+require (
+	github.com/mozilla-ai/mcpd-plugins-sdk-go v0.0.2
+	google.golang.org/protobuf v1.36.10
+)
+
+require (
+	golang.org/x/net v0.44.0 // indirect
+	golang.org/x/sys v0.36.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251002232023-7c0ddcbb5797 // indirect
+	google.golang.org/grpc v1.75.1 // indirect
+)
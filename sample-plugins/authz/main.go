@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultSubjectHeader = "X-Authenticated-Subject"
+	defaultRolesHeader   = "X-Authenticated-Roles"
+)
+
+// denyBody is the structured 403 response body.
+type denyBody struct {
+	Error   string `json:"error"`
+	Reason  string `json:"reason"`
+	Rule    string `json:"rule,omitempty"`
+	Subject string `json:"subject,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+}
+
+// AuthZPlugin authorizes requests against declarative method/path/role rules.
+type AuthZPlugin struct {
+	pluginv1.BasePlugin
+
+	mu            sync.RWMutex
+	rules         []policyRule
+	defaultAllow  bool
+	subjectHeader string
+	rolesHeader   string
+	initialized   bool
+}
+
+func newAuthZPlugin() *AuthZPlugin {
+	return &AuthZPlugin{
+		subjectHeader: defaultSubjectHeader,
+		rolesHeader:   defaultRolesHeader,
+	}
+}
+
+func (p *AuthZPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "authz",
+		Version:     "1.0.0",
+		Description: "Authorizes requests using method, path and role rules",
+	}, nil
+}
+
+func (p *AuthZPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *AuthZPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	var rules []policyRule
+	if rulesStr, exists := custom["rules"]; exists {
+		parsed, err := parsePolicy(rulesStr)
+		if err != nil {
+			return nil, fmt.Errorf("authz plugin configuration failed: %w", err)
+		}
+		rules = parsed
+	}
+
+	defaultAllow := false
+	switch effect := strings.ToLower(custom["default_effect"]); effect {
+	case "", effectDeny:
+	case effectAllow:
+		defaultAllow = true
+	default:
+		return nil, fmt.Errorf("authz plugin configuration failed: invalid default_effect %q", effect)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = rules
+	p.defaultAllow = defaultAllow
+	p.subjectHeader = valueOrDefault(custom["subject_header"], defaultSubjectHeader)
+	p.rolesHeader = valueOrDefault(custom["roles_header"], defaultRolesHeader)
+	p.initialized = true
+
+	log.Printf("AuthZ plugin initialized with %d rules (default allow: %t)", len(rules), defaultAllow)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthZPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("AuthZ plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthZPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("authz plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthZPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("authz plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *AuthZPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*pluginv1.HTTPResponse, error) {
+	log.Printf("AuthZ handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("authz plugin not initialized")
+	}
+
+	who := principal{
+		subject: headerValue(req.Headers, p.subjectHeader),
+		roles:   splitRoles(headerValue(req.Headers, p.rolesHeader)),
+	}
+
+	// Normalize the path so "/public/../admin" can't slip past a "/public/**" rule.
+	reqPath := path.Clean("/" + req.Path)
+	method := strings.ToUpper(req.Method)
+
+	d := evaluate(p.rules, p.defaultAllow, method, reqPath, who)
+	if !d.allowed {
+		log.Printf("AuthZ denied %s %s for subject %q: %s (rule: %s)", method, reqPath, who.subject, d.reason, d.rule)
+
+		body, err := json.Marshal(denyBody{
+			Error:   "Forbidden",
+			Reason:  d.reason,
+			Rule:    d.rule,
+			Subject: who.subject,
+			Method:  method,
+			Path:    reqPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode deny response: %w", err)
+		}
+
+		return &pluginv1.HTTPResponse{
+			Continue:   false,
+			StatusCode: 403,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Body: body,
+		}, nil
+	}
+
+	log.Printf("AuthZ allowed %s %s for subject %q (rule: %s)", method, reqPath, who.subject, d.rule)
+
+	return &pluginv1.HTTPResponse{
+		Continue: true,
+	}, nil
+}
+
+// splitRoles parses the comma-separated roles header written by the authn plugin.
+func splitRoles(header string) []string {
+	if header == "" {
+		return nil
+	}
+
+	var roles []string
+	for _, r := range strings.Split(header, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, r)
+		}
+	}
+
+	return roles
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newAuthZPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
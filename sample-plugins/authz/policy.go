@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+const (
+	effectAllow = "allow"
+	effectDeny  = "deny"
+)
+
+// ruleConfig is the JSON form of an entry in the "rules" custom config value.
+type ruleConfig struct {
+	Name     string   `json:"name"`
+	Effect   string   `json:"effect"`
+	Methods  []string `json:"methods"`
+	Paths    []string `json:"paths"`
+	Roles    []string `json:"roles"`
+	Subjects []string `json:"subjects"`
+}
+
+// policyRule grants or denies access to method and path patterns for a set of roles or subjects.
+type policyRule struct {
+	name     string
+	effect   string
+	methods  []string
+	paths    []string
+	roles    []string
+	subjects []string
+}
+
+// principal is the caller identity injected by the authn stage.
+type principal struct {
+	subject string
+	roles   []string
+}
+
+// decision is the outcome of evaluating the policy for a request.
+type decision struct {
+	allowed bool
+	rule    string
+	reason  string
+}
+
+// parsePolicy decodes and validates a JSON array of rules.
+func parsePolicy(raw string) ([]policyRule, error) {
+	var cfgs []ruleConfig
+	if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	rules := make([]policyRule, 0, len(cfgs))
+	for i, c := range cfgs {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i)
+		}
+
+		effect := strings.ToLower(c.Effect)
+		if effect == "" {
+			effect = effectAllow
+		}
+
+		if effect != effectAllow && effect != effectDeny {
+			return nil, fmt.Errorf("rule %q: effect must be %q or %q", name, effectAllow, effectDeny)
+		}
+
+		if len(c.Paths) == 0 {
+			return nil, fmt.Errorf("rule %q: at least one path pattern is required", name)
+		}
+
+		for _, pattern := range c.Paths {
+			if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), "/"); err != nil {
+				return nil, fmt.Errorf("rule %q: invalid path pattern %q", name, pattern)
+			}
+		}
+
+		methods := make([]string, 0, len(c.Methods))
+		for _, m := range c.Methods {
+			methods = append(methods, strings.ToUpper(m))
+		}
+
+		rules = append(rules, policyRule{
+			name:     name,
+			effect:   effect,
+			methods:  methods,
+			paths:    c.Paths,
+			roles:    c.Roles,
+			subjects: c.Subjects,
+		})
+	}
+
+	return rules, nil
+}
+
+// evaluate applies the first rule matching the request; defaultAllow decides when none match.
+func evaluate(rules []policyRule, defaultAllow bool, method string, reqPath string, who principal) decision {
+	for _, r := range rules {
+		if !r.matchesRequest(method, reqPath) || !r.matchesPrincipal(who) {
+			continue
+		}
+
+		if r.effect == effectDeny {
+			return decision{rule: r.name, reason: "denied by rule"}
+		}
+
+		return decision{allowed: true, rule: r.name}
+	}
+
+	if defaultAllow {
+		return decision{allowed: true}
+	}
+
+	if who.subject == "" {
+		return decision{reason: "no authenticated subject"}
+	}
+
+	return decision{reason: "no rule grants access"}
+}
+
+// matchesRequest reports whether the method and path fall under the rule.
+func (r policyRule) matchesRequest(method string, reqPath string) bool {
+	if len(r.methods) > 0 && !slices.Contains(r.methods, "*") && !slices.Contains(r.methods, method) {
+		return false
+	}
+
+	return slices.ContainsFunc(r.paths, func(pattern string) bool {
+		return matchPath(pattern, reqPath)
+	})
+}
+
+// matchesPrincipal reports whether the caller is covered by the rule.
+// A rule without roles or subjects applies to every caller, authenticated or not.
+func (r policyRule) matchesPrincipal(who principal) bool {
+	if len(r.roles) == 0 && len(r.subjects) == 0 {
+		return true
+	}
+
+	if who.subject != "" && slices.Contains(r.subjects, who.subject) {
+		return true
+	}
+
+	return slices.ContainsFunc(who.roles, func(role string) bool {
+		return slices.Contains(r.roles, role)
+	})
+}
+
+// matchPath matches a request path against a pattern.
+// Patterns use path.Match syntax per segment; a trailing "/**" matches the prefix and anything below it.
+func matchPath(pattern string, reqPath string) bool {
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if base == "" {
+			return true
+		}
+
+		// Compare the leading segments so wildcards in the base still apply.
+		baseSegments := strings.Count(base, "/") + 1
+		reqSegments := strings.Split(reqPath, "/")
+		if len(reqSegments) < baseSegments {
+			return false
+		}
+
+		matched, _ := path.Match(base, strings.Join(reqSegments[:baseSegments], "/"))
+		return matched
+	}
+
+	matched, _ := path.Match(pattern, reqPath)
+	return matched
+}
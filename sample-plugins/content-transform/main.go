@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ContentTransformPlugin rewrites request and response bodies.
+type ContentTransformPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	request     *requestTransform
+	response    *responseTransform
+	initialized bool
+}
+
+func newContentTransformPlugin() *ContentTransformPlugin {
+	return &ContentTransformPlugin{}
+}
+
+func (p *ContentTransformPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "content-transform",
+		Version:     "1.0.0",
+		Description: "Redacts and injects request JSON fields and templates response bodies",
+	}, nil
+}
+
+func (p *ContentTransformPlugin) GetCapabilities(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest, pluginv1.FlowResponse},
+	}, nil
+}
+
+func (p *ContentTransformPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	request, err := newRequestTransform(custom)
+	if err != nil {
+		return nil, fmt.Errorf("content transform plugin configuration failed: %w", err)
+	}
+
+	response, err := newResponseTransform(custom)
+	if err != nil {
+		return nil, fmt.Errorf("content transform plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.request = request
+	p.response = response
+	p.initialized = true
+
+	log.Printf(
+		"Content transform plugin initialized (redact: %d, inject: %d, template: %t)",
+		len(request.redact),
+		len(request.inject),
+		response.template != nil,
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *ContentTransformPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Content transform plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.request = nil
+	p.response = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *ContentTransformPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("content transform plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *ContentTransformPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("content transform plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *ContentTransformPlugin) HandleRequest(
+	ctx context.Context,
+	req *pluginv1.HTTPRequest,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("Content transform handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	t := p.request
+	p.mu.RUnlock()
+
+	if t == nil || !t.enabled() || len(req.Body) == 0 || isEncoded(req.Headers) ||
+		!isJSON(headerValue(req.Headers, "Content-Type")) {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	body, changed, err := t.apply(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	modified := proto.Clone(req).(*pluginv1.HTTPRequest)
+	modified.Body = body
+	if headerValue(modified.Headers, "Content-Length") != "" {
+		deleteHeader(modified.Headers, "Content-Length")
+		modified.Headers["Content-Length"] = strconv.Itoa(len(body))
+	}
+
+	log.Printf("Content transform rewrote request body: %d -> %d bytes", len(req.Body), len(body))
+
+	return &pluginv1.HTTPResponse{
+		Continue:        true,
+		ModifiedRequest: modified,
+	}, nil
+}
+
+func (p *ContentTransformPlugin) HandleResponse(
+	ctx context.Context,
+	resp *pluginv1.HTTPResponse,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("Content transform handling response: %d", resp.StatusCode)
+
+	p.mu.RLock()
+	t := p.response
+	p.mu.RUnlock()
+
+	if t == nil || !t.enabled() {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	headers, body, changed, err := t.apply(resp.StatusCode, resp.Headers, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if !changed {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	log.Printf("Content transform rewrote response body: %d -> %d bytes", len(resp.Body), len(body))
+
+	return &pluginv1.HTTPResponse{
+		Continue:   true,
+		StatusCode: resp.StatusCode,
+		Headers:    headers,
+		Body:       body,
+	}, nil
+}
+
+// isJSON reports whether a Content-Type denotes JSON, including "+json" suffixed media types.
+func isJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newContentTransformPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
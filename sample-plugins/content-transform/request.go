@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const defaultRedactValue = "[REDACTED]"
+
+// requestTransform rewrites fields in JSON request bodies.
+type requestTransform struct {
+	redact      [][]string
+	redactValue string
+	inject      map[string]any
+	injectPaths map[string][]string
+}
+
+// newRequestTransform builds the request transform from the redact_* and inject_fields config keys.
+func newRequestTransform(cfg map[string]string) (*requestTransform, error) {
+	t := &requestTransform{
+		redactValue: defaultRedactValue,
+		inject:      make(map[string]any),
+		injectPaths: make(map[string][]string),
+	}
+
+	if v, exists := cfg["redact_value"]; exists {
+		t.redactValue = v
+	}
+
+	for _, field := range strings.Split(cfg["redact_fields"], ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		t.redact = append(t.redact, strings.Split(field, "."))
+	}
+
+	if raw := cfg["inject_fields"]; raw != "" {
+		if err := decodeJSON([]byte(raw), &t.inject); err != nil {
+			return nil, fmt.Errorf("invalid inject_fields: %w", err)
+		}
+
+		for field := range t.inject {
+			if field == "" {
+				return nil, fmt.Errorf("invalid inject_fields: empty field path")
+			}
+			t.injectPaths[field] = strings.Split(field, ".")
+		}
+	}
+
+	return t, nil
+}
+
+// enabled reports whether the transform would change anything.
+func (t *requestTransform) enabled() bool {
+	return len(t.redact) > 0 || len(t.inject) > 0
+}
+
+// apply returns the transformed body and whether it changed.
+// Bodies that are not JSON objects are returned unchanged.
+func (t *requestTransform) apply(body []byte) ([]byte, bool, error) {
+	var doc map[string]any
+	if err := decodeJSON(body, &doc); err != nil {
+		return body, false, nil
+	}
+
+	changed := false
+
+	for _, path := range t.redact {
+		if redactField(doc, path, t.redactValue) {
+			changed = true
+		}
+	}
+
+	for field, value := range t.inject {
+		setField(doc, t.injectPaths[field], value)
+		changed = true
+	}
+
+	if !changed {
+		return body, false, nil
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode transformed body: %w", err)
+	}
+
+	return out, true, nil
+}
+
+// redactField replaces the value at path if present. A "*" segment matches every key of an object.
+func redactField(doc map[string]any, path []string, replacement string) bool {
+	key, rest := path[0], path[1:]
+
+	keys := []string{key}
+	if key == "*" {
+		keys = keys[:0]
+		for k := range doc {
+			keys = append(keys, k)
+		}
+	}
+
+	changed := false
+	for _, k := range keys {
+		v, ok := doc[k]
+		if !ok {
+			continue
+		}
+
+		if len(rest) == 0 {
+			doc[k] = replacement
+			changed = true
+			continue
+		}
+
+		if child, ok := v.(map[string]any); ok && redactField(child, rest, replacement) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// setField sets the value at path, creating intermediate objects and replacing non-object values on the way.
+func setField(doc map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		child, ok := doc[key].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			doc[key] = child
+		}
+		doc = child
+	}
+
+	doc[path[len(path)-1]] = value
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number so large integer IDs survive
+// re-encoding unchanged instead of being rounded through float64.
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+
+	return nil
+}
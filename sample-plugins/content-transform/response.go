@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const defaultTemplateContentType = "application/json"
+
+// templateData is the value the response template is executed against.
+type templateData struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       string
+	JSON       any
+}
+
+// responseTransform rewrites response bodies using a template.
+type responseTransform struct {
+	template            *template.Template
+	templateContentType string
+	contentType         string
+}
+
+// newResponseTransform builds the response transform from the response_* and template_* config keys.
+func newResponseTransform(cfg map[string]string) (*responseTransform, error) {
+	t := &responseTransform{
+		templateContentType: valueOrDefault(cfg["template_content_type"], defaultTemplateContentType),
+		contentType:         cfg["response_content_type"],
+	}
+
+	if raw := cfg["response_template"]; raw != "" {
+		tmpl, err := template.New("response").
+			Option("missingkey=zero").
+			Funcs(template.FuncMap{"json": toJSON}).
+			Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response_template: %w", err)
+		}
+		t.template = tmpl
+	}
+
+	return t, nil
+}
+
+// enabled reports whether the transform would change anything.
+func (t *responseTransform) enabled() bool {
+	return t.template != nil
+}
+
+// apply returns the transformed headers and body and whether either changed.
+// The input headers are never modified.
+func (t *responseTransform) apply(status int32, headers map[string]string, body []byte) (
+	map[string]string,
+	[]byte,
+	bool,
+	error,
+) {
+	out := maps.Clone(headers)
+	if out == nil {
+		out = make(map[string]string)
+	}
+	changed := false
+
+	// A compressed body can't be rendered as text; templating it would corrupt the response.
+	if t.template != nil && !isEncoded(out) && strings.Contains(headerValue(out, "Content-Type"), t.templateContentType) {
+		rendered, err := t.render(status, out, body)
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		body = rendered
+		if t.contentType != "" {
+			deleteHeader(out, "Content-Type")
+			out["Content-Type"] = t.contentType
+		}
+		changed = true
+	}
+
+	if changed {
+		deleteHeader(out, "Content-Length")
+		out["Content-Length"] = strconv.Itoa(len(body))
+	}
+
+	return out, body, changed, nil
+}
+
+// render executes the template; JSON is the decoded body, or nil when the body is not JSON.
+func (t *responseTransform) render(status int32, headers map[string]string, body []byte) ([]byte, error) {
+	data := templateData{
+		StatusCode: int(status),
+		Headers:    headers,
+		Body:       string(body),
+	}
+
+	if len(body) > 0 {
+		var v any
+		if err := decodeJSON(body, &v); err == nil {
+			data.JSON = v
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := t.template.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render response template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toJSON is the "json" template function; it encodes any value, including the decoded body.
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// isEncoded reports whether the body has a Content-Encoding other than identity.
+func isEncoded(headers map[string]string) bool {
+	enc := strings.TrimSpace(headerValue(headers, "Content-Encoding"))
+	return enc != "" && !strings.EqualFold(enc, "identity")
+}
+
+// deleteHeader removes every case variant of a header.
+func deleteHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
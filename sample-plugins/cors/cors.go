@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults cover MCP's streamable HTTP transport: DELETE ends a session, and clients need to send and read
+// the session and protocol version headers.
+const (
+	defaultAllowedMethods = "GET, POST, DELETE"
+	defaultAllowedHeaders = "Content-Type, Authorization, Accept, Last-Event-ID, Mcp-Session-Id, Mcp-Protocol-Version"
+	defaultExposedHeaders = "Mcp-Session-Id"
+	defaultMaxAge         = 10 * time.Minute
+)
+
+// corsPolicy is the parsed CustomConfig of the plugin.
+type corsPolicy struct {
+	anyOrigin        bool
+	origins          []string
+	methods          []string
+	headers          []string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           time.Duration
+}
+
+func newCORSPolicy(custom map[string]string) (*corsPolicy, error) {
+	p := &corsPolicy{maxAge: defaultMaxAge}
+
+	for _, o := range splitList(custom["allowed_origins"]) {
+		if o == "*" {
+			p.anyOrigin = true
+			continue
+		}
+		if !strings.HasPrefix(o, "http://") && !strings.HasPrefix(o, "https://") {
+			return nil, fmt.Errorf("invalid origin %q: must be * or a scheme://host[:port] origin", o)
+		}
+		p.origins = append(p.origins, strings.ToLower(strings.TrimSuffix(o, "/")))
+	}
+
+	if !p.anyOrigin && len(p.origins) == 0 {
+		return nil, errors.New("allowed_origins is required")
+	}
+
+	// Responses don't carry the request's Origin, and mcpd doesn't pass it to the response flow, so actual
+	// responses can only get an Access-Control-Allow-Origin that is the same for every request.
+	if !p.anyOrigin && len(p.origins) > 1 {
+		return nil, errors.New(
+			"allowed_origins must be * or a single origin, since the host doesn't pass the request's Origin " +
+				"to the response flow",
+		)
+	}
+
+	for _, m := range splitList(valueOrDefault(custom["allowed_methods"], defaultAllowedMethods)) {
+		p.methods = append(p.methods, strings.ToUpper(m))
+	}
+
+	p.headers = splitList(valueOrDefault(custom["allowed_headers"], defaultAllowedHeaders))
+
+	p.exposedHeaders = strings.Join(splitList(valueOrDefault(custom["exposed_headers"], defaultExposedHeaders)), ", ")
+
+	if v, exists := custom["allow_credentials"]; exists {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow_credentials %q", v)
+		}
+		p.allowCredentials = b
+	}
+
+	// Browsers reject credentialed responses with a wildcard origin, so echoing every origin would be the
+	// only way to make it work, and that would let any site make authenticated calls.
+	if p.anyOrigin && p.allowCredentials {
+		return nil, errors.New("allow_credentials cannot be used with allowed_origins *")
+	}
+
+	if v, exists := custom["max_age"]; exists {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid max_age %q", v)
+		}
+		p.maxAge = d
+	}
+
+	return p, nil
+}
+
+func (p *corsPolicy) originAllowed(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	return p.anyOrigin || slices.Contains(p.origins, strings.ToLower(origin))
+}
+
+func (p *corsPolicy) methodAllowed(method string) bool {
+	return slices.Contains(p.methods, strings.ToUpper(strings.TrimSpace(method)))
+}
+
+// headersAllowed reports whether every header in a comma-separated Access-Control-Request-Headers value
+// is allowed, returning the first one that isn't.
+func (p *corsPolicy) headersAllowed(requested string) (string, bool) {
+	for _, h := range splitList(requested) {
+		allowed := slices.ContainsFunc(p.headers, func(name string) bool { return strings.EqualFold(name, h) })
+		if !allowed {
+			return h, false
+		}
+	}
+
+	return "", true
+}
+
+// allowOrigin is the Access-Control-Allow-Origin value: * or the single allowed origin. It is the same for
+// every request, so it can be sent without knowing the request's Origin.
+func (p *corsPolicy) allowOrigin() string {
+	if p.anyOrigin {
+		return "*"
+	}
+
+	return p.origins[0]
+}
+
+// preflightHeaders are the headers of a successful preflight response.
+func (p *corsPolicy) preflightHeaders() map[string]string {
+	headers := map[string]string{
+		"Access-Control-Allow-Origin":  p.allowOrigin(),
+		"Access-Control-Allow-Methods": strings.Join(p.methods, ", "),
+		"Access-Control-Allow-Headers": strings.Join(p.headers, ", "),
+		"Access-Control-Max-Age":       strconv.Itoa(int(p.maxAge.Seconds())),
+		"Vary":                         "Origin, Access-Control-Request-Method, Access-Control-Request-Headers",
+	}
+
+	if p.allowCredentials {
+		headers["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	return headers
+}
+
+// addResponseHeaders sets the CORS headers of an actual (non-preflight) response.
+func (p *corsPolicy) addResponseHeaders(headers map[string]string) {
+	deleteHeader(headers, "Access-Control-Allow-Origin")
+	headers["Access-Control-Allow-Origin"] = p.allowOrigin()
+
+	if p.allowCredentials {
+		deleteHeader(headers, "Access-Control-Allow-Credentials")
+		headers["Access-Control-Allow-Credentials"] = "true"
+	}
+
+	if p.exposedHeaders != "" {
+		deleteHeader(headers, "Access-Control-Expose-Headers")
+		headers["Access-Control-Expose-Headers"] = p.exposedHeaders
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// deleteHeader removes every case variant of a header.
+func deleteHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// CORSPlugin answers CORS preflight requests and adds CORS headers to responses.
+type CORSPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	policy      *corsPolicy
+	initialized bool
+}
+
+// preflightRejection is the JSON body returned for preflights that aren't allowed.
+type preflightRejection struct {
+	Error  string `json:"error"`
+	Reason string `json:"reason"`
+}
+
+func newCORSPlugin() *CORSPlugin {
+	return &CORSPlugin{}
+}
+
+func (p *CORSPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "cors",
+		Version:     "1.0.0",
+		Description: "Answers CORS preflight requests and adds CORS headers to responses",
+	}, nil
+}
+
+func (p *CORSPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest, pluginv1.FlowResponse},
+	}, nil
+}
+
+func (p *CORSPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	pol, err := newCORSPolicy(custom)
+	if err != nil {
+		return nil, fmt.Errorf("cors plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policy = pol
+	p.initialized = true
+
+	log.Printf("CORS plugin initialized for origin: %s (credentials: %t)", pol.allowOrigin(), pol.allowCredentials)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *CORSPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("CORS plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policy = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *CORSPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("cors plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *CORSPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("cors plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *CORSPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*pluginv1.HTTPResponse, error) {
+	log.Printf("CORS handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	pol := p.policy
+	p.mu.RUnlock()
+
+	if pol == nil {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	origin := headerValue(req.Headers, "Origin")
+	requestMethod := headerValue(req.Headers, "Access-Control-Request-Method")
+	if strings.EqualFold(req.Method, http.MethodOptions) && origin != "" && requestMethod != "" {
+		return p.preflight(pol, origin, requestMethod, headerValue(req.Headers, "Access-Control-Request-Headers"))
+	}
+
+	return &pluginv1.HTTPResponse{Continue: true}, nil
+}
+
+// preflight answers an OPTIONS preflight directly, without reaching the upstream.
+func (p *CORSPlugin) preflight(
+	pol *corsPolicy,
+	origin string,
+	method string,
+	requestHeaders string,
+) (*pluginv1.HTTPResponse, error) {
+	reason := ""
+	switch {
+	case !pol.originAllowed(origin):
+		reason = fmt.Sprintf("origin %s is not allowed", origin)
+	case !pol.methodAllowed(method):
+		reason = fmt.Sprintf("method %s is not allowed", method)
+	default:
+		if h, ok := pol.headersAllowed(requestHeaders); !ok {
+			reason = fmt.Sprintf("header %s is not allowed", h)
+		}
+	}
+
+	if reason != "" {
+		log.Printf("CORS preflight rejected: %s", reason)
+
+		body, err := json.Marshal(preflightRejection{Error: "CORS preflight rejected", Reason: reason})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode rejection: %w", err)
+		}
+
+		return &pluginv1.HTTPResponse{
+			Continue:   false,
+			StatusCode: http.StatusForbidden,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"Vary":         "Origin",
+			},
+			Body: body,
+		}, nil
+	}
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: http.StatusNoContent,
+		Headers:    pol.preflightHeaders(),
+	}, nil
+}
+
+func (p *CORSPlugin) HandleResponse(ctx context.Context, resp *pluginv1.HTTPResponse) (*pluginv1.HTTPResponse, error) {
+	p.mu.RLock()
+	pol := p.policy
+	p.mu.RUnlock()
+
+	if pol == nil {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	headers := maps.Clone(resp.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	pol.addResponseHeaders(headers)
+
+	return &pluginv1.HTTPResponse{
+		Continue:   true,
+		StatusCode: resp.StatusCode,
+		Headers:    headers,
+		Body:       resp.Body,
+	}, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newCORSPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fault is the outcome of a single roll.
+type fault int
+
+const (
+	faultNone fault = iota
+	faultFail
+	faultDrop
+	faultError
+)
+
+func (f fault) String() string {
+	switch f {
+	case faultFail:
+		return "fail"
+	case faultDrop:
+		return "drop"
+	case faultError:
+		return "error"
+	default:
+		return "none"
+	}
+}
+
+// faultConfig is the parsed CustomConfig of the plugin.
+type faultConfig struct {
+	latency       time.Duration
+	latencyJitter time.Duration
+	latencyRate   float64
+	failRate      float64
+	dropRate      float64
+	errorRate     float64
+	errorStatus   int
+	request       bool
+	response      bool
+}
+
+func parseFaultConfig(custom map[string]string) (*faultConfig, error) {
+	cfg := &faultConfig{
+		latencyRate: 1,
+		errorStatus: http.StatusServiceUnavailable,
+		request:     true,
+		response:    true,
+	}
+
+	var err error
+	if cfg.latency, err = parseDuration(custom, "latency"); err != nil {
+		return nil, err
+	}
+	if cfg.latencyJitter, err = parseDuration(custom, "latency_jitter"); err != nil {
+		return nil, err
+	}
+
+	rates := []struct {
+		key string
+		dst *float64
+	}{
+		{"latency_rate", &cfg.latencyRate},
+		{"fail_rate", &cfg.failRate},
+		{"drop_rate", &cfg.dropRate},
+		{"error_rate", &cfg.errorRate},
+	}
+	for _, r := range rates {
+		v, exists := custom[r.key]
+		if !exists {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("invalid %s %q: must be between 0 and 1", r.key, v)
+		}
+		*r.dst = f
+	}
+
+	if sum := cfg.failRate + cfg.dropRate + cfg.errorRate; sum > 1 {
+		return nil, fmt.Errorf("fail_rate, drop_rate and error_rate add up to %g: must not exceed 1", sum)
+	}
+
+	if v, exists := custom["error_status"]; exists {
+		status, err := strconv.Atoi(v)
+		if err != nil || status < 400 || status > 599 {
+			return nil, fmt.Errorf("invalid error_status %q: must be a 4xx or 5xx status", v)
+		}
+		cfg.errorStatus = status
+	}
+
+	if v, exists := custom["flows"]; exists {
+		cfg.request, cfg.response = false, false
+		for _, f := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(f)) {
+			case "request":
+				cfg.request = true
+			case "response":
+				cfg.response = true
+			default:
+				return nil, fmt.Errorf("invalid flow %q: must be request or response", f)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseDuration(custom map[string]string, key string) (time.Duration, error) {
+	v, exists := custom[key]
+	if !exists {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+
+	return d, nil
+}
+
+// injector rolls faults from a shared random source.
+type injector struct {
+	cfg *faultConfig
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func newInjector(cfg *faultConfig, seed uint64) *injector {
+	return &injector{
+		cfg: cfg,
+		rnd: rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// delay returns the latency to inject, or zero.
+func (i *injector) delay() time.Duration {
+	if i.cfg.latency == 0 && i.cfg.latencyJitter == 0 {
+		return 0
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.rnd.Float64() >= i.cfg.latencyRate {
+		return 0
+	}
+
+	d := i.cfg.latency
+	if i.cfg.latencyJitter > 0 {
+		d += time.Duration(i.rnd.Int64N(int64(i.cfg.latencyJitter) + 1))
+	}
+
+	return d
+}
+
+// roll picks at most one fault, using the configured rates as disjoint probabilities.
+func (i *injector) roll() fault {
+	i.mu.Lock()
+	r := i.rnd.Float64()
+	i.mu.Unlock()
+
+	switch {
+	case r < i.cfg.failRate:
+		return faultFail
+	case r < i.cfg.failRate+i.cfg.dropRate:
+		return faultDrop
+	case r < i.cfg.failRate+i.cfg.dropRate+i.cfg.errorRate:
+		return faultError
+	default:
+		return faultNone
+	}
+}
+
+// sleep waits for d, returning early with the context's error if it's cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
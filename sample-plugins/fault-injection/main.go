@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// errInjected is returned from the handlers when a "fail" fault is rolled.
+var errInjected = errors.New("fault injection: injected plugin failure")
+
+// FaultInjectionPlugin injects latency and failures into the pipeline.
+//
+// It's meant for exercising how the host treats required and optional plugins, timeouts and
+// circuit breakers before relying on them in production. Never enable it on real traffic.
+type FaultInjectionPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	injector    *injector
+	initialized bool
+}
+
+// faultBody is the JSON body of an injected error response.
+type faultBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+func newFaultInjectionPlugin() *FaultInjectionPlugin {
+	return &FaultInjectionPlugin{}
+}
+
+func (p *FaultInjectionPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "fault-injection",
+		Version:     "1.0.0",
+		Description: "Injects latency, errors and failed replies for resilience testing",
+	}, nil
+}
+
+func (p *FaultInjectionPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest, pluginv1.FlowResponse},
+	}, nil
+}
+
+func (p *FaultInjectionPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	faults, err := parseFaultConfig(custom)
+	if err != nil {
+		return nil, fmt.Errorf("fault injection plugin configuration failed: %w", err)
+	}
+
+	seed := rand.Uint64()
+	if seedStr, exists := custom["seed"]; exists {
+		seed, err = strconv.ParseUint(seedStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("fault injection plugin configuration failed: invalid seed %q", seedStr)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.injector = newInjector(faults, seed)
+	p.initialized = true
+
+	log.Printf(
+		"Fault injection plugin initialized (latency: %v+%v at %g, fail: %g, drop: %g, error: %g -> %d)",
+		faults.latency,
+		faults.latencyJitter,
+		faults.latencyRate,
+		faults.failRate,
+		faults.dropRate,
+		faults.errorRate,
+		faults.errorStatus,
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *FaultInjectionPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Fault injection plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.injector = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *FaultInjectionPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("fault injection plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *FaultInjectionPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("fault injection plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *FaultInjectionPlugin) HandleRequest(
+	ctx context.Context,
+	req *pluginv1.HTTPRequest,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("Fault injection handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	inj := p.injector
+	p.mu.RUnlock()
+
+	if inj == nil || !inj.cfg.request {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	if resp, err := p.inject(ctx, inj, "request"); resp != nil || err != nil {
+		return resp, err
+	}
+
+	return &pluginv1.HTTPResponse{Continue: true}, nil
+}
+
+func (p *FaultInjectionPlugin) HandleResponse(
+	ctx context.Context,
+	resp *pluginv1.HTTPResponse,
+) (*pluginv1.HTTPResponse, error) {
+	p.mu.RLock()
+	inj := p.injector
+	p.mu.RUnlock()
+
+	if inj == nil || !inj.cfg.response {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	if out, err := p.inject(ctx, inj, "response"); out != nil || err != nil {
+		return out, err
+	}
+
+	return p.BasePlugin.HandleResponse(ctx, resp)
+}
+
+// inject applies latency and then at most one fault for the given flow.
+// It returns nil and no error when no fault was injected and the flow should carry on as normal.
+func (p *FaultInjectionPlugin) inject(
+	ctx context.Context,
+	inj *injector,
+	flow string,
+) (*pluginv1.HTTPResponse, error) {
+	if d := inj.delay(); d > 0 {
+		log.Printf("Fault injection: delaying %s by %v", flow, d)
+		if err := sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+
+	f := inj.roll()
+	if f == faultNone {
+		return nil, nil
+	}
+
+	log.Printf("Fault injection: injecting %s on %s", f, flow)
+
+	switch f {
+	case faultFail:
+		return nil, errInjected
+	case faultDrop:
+		// Neither Continue nor a status: a malformed reply the host has to cope with.
+		return &pluginv1.HTTPResponse{}, nil
+	default:
+		status := inj.cfg.errorStatus
+		body, _ := json.Marshal(faultBody{
+			Error:   http.StatusText(status),
+			Message: "fault injected by fault-injection plugin",
+		})
+
+		return &pluginv1.HTTPResponse{
+			Continue:   false,
+			StatusCode: int32(status),
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Body: body,
+		}, nil
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newFaultInjectionPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -3,26 +3,37 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"log"
-	"net"
+	"maps"
 	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 
 	pb "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
-	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// headerTransformerPlugin renames, removes and sets request headers according to configured rules.
+// Without a "rules" config value it adds X-Transformed-By and X-Original-Path to every request.
+// Response handling uses the BasePlugin default.
 type headerTransformerPlugin struct {
-	pb.UnimplementedPluginServer
+	pb.BasePlugin
+
+	mu          sync.RWMutex
+	rules       []headerRule
+	initialized bool
+}
+
+func newHeaderTransformerPlugin() *headerTransformerPlugin {
+	return &headerTransformerPlugin{}
 }
 
 func (p *headerTransformerPlugin) GetMetadata(_ context.Context, _ *emptypb.Empty) (*pb.Metadata, error) {
 	return &pb.Metadata{
 		Name:        "header-transformer",
 		Version:     "1.0.0",
-		Description: "Transforms request headers by adding custom headers",
+		Description: "Transforms request headers using configurable rename, remove and set rules",
 		CommitHash:  "abc123",
 	}, nil
 }
@@ -33,24 +44,93 @@ func (p *headerTransformerPlugin) GetCapabilities(_ context.Context, _ *emptypb.
 	}, nil
 }
 
-func (p *headerTransformerPlugin) CheckHealth(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+func (p *headerTransformerPlugin) Configure(_ context.Context, cfg *pb.PluginConfig) (*emptypb.Empty, error) {
+	raw := cfg.GetCustomConfig()["rules"]
+	if raw == "" {
+		raw = defaultRules
+	}
+
+	rules, err := parseRules(raw)
+	if err != nil {
+		return nil, fmt.Errorf("header transformer plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = rules
+	p.initialized = true
+	log.Printf("Header transformer plugin initialized with %d rules", len(rules))
+
 	return &emptypb.Empty{}, nil
 }
 
-func (p *headerTransformerPlugin) CheckReady(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+func (p *headerTransformerPlugin) Stop(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Header transformer plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = nil
+	p.initialized = false
+
 	return &emptypb.Empty{}, nil
 }
 
-func (p *headerTransformerPlugin) Configure(_ context.Context, _ *pb.PluginConfig) (*emptypb.Empty, error) {
+func (p *headerTransformerPlugin) CheckHealth(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("header transformer plugin not initialized")
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
-func (p *headerTransformerPlugin) Stop(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+func (p *headerTransformerPlugin) CheckReady(_ context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("header transformer plugin not ready")
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
 func (p *headerTransformerPlugin) HandleRequest(_ context.Context, req *pb.HTTPRequest) (*pb.HTTPResponse, error) {
-	// Create a modified request with additional headers.
+	p.mu.RLock()
+	initialized, rules := p.initialized, p.rules
+	p.mu.RUnlock()
+
+	if !initialized {
+		return nil, fmt.Errorf("header transformer plugin not initialized")
+	}
+
+	// Apply every matching rule, in order, to a copy of the headers.
+	headers := maps.Clone(req.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+
+	var applied []string
+	for i := range rules {
+		if !rules[i].matches(req) {
+			continue
+		}
+
+		if err := rules[i].apply(req, req.Headers, headers); err != nil {
+			return nil, err
+		}
+		applied = append(applied, rules[i].name)
+	}
+
+	if maps.Equal(headers, req.Headers) {
+		return &pb.HTTPResponse{Continue: true}, nil
+	}
+
+	// Create a modified request with the transformed headers.
 	modifiedReq := &pb.HTTPRequest{
 		Method:     req.Method,
 		Url:        req.Url,
@@ -58,19 +138,10 @@ func (p *headerTransformerPlugin) HandleRequest(_ context.Context, req *pb.HTTPR
 		Body:       req.Body,
 		RemoteAddr: req.RemoteAddr,
 		RequestUri: req.RequestUri,
-		Headers:    make(map[string]string),
+		Headers:    headers,
 	}
 
-	// Copy existing headers.
-	for k, v := range req.Headers {
-		modifiedReq.Headers[k] = v
-	}
-
-	// Add custom transformation headers.
-	modifiedReq.Headers["X-Transformed-By"] = "header-transformer-plugin"
-	modifiedReq.Headers["X-Original-Path"] = req.Path
-
-	log.Printf("Transformed request: added headers X-Transformed-By and X-Original-Path")
+	log.Printf("Transformed request %s %s: applied rules %v", req.Method, req.Path, applied)
 
 	// Return response with modified request.
 	return &pb.HTTPResponse{
@@ -80,59 +151,29 @@ func (p *headerTransformerPlugin) HandleRequest(_ context.Context, req *pb.HTTPR
 	}, nil
 }
 
-func (p *headerTransformerPlugin) HandleResponse(_ context.Context, resp *pb.HTTPResponse) (*pb.HTTPResponse, error) {
-	// Pass through unchanged.
-	return resp, nil
+// removeStaleSocket deletes a socket left behind by a previous run that didn't exit cleanly, which would
+// otherwise make listening fail with "address already in use". Serve only removes the socket on a clean exit.
+func removeStaleSocket(args []string) {
+	flags := flag.NewFlagSet("header-transformer", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	address := flags.String("address", "", "")
+	network := flags.String("network", "unix", "")
+
+	// Serve reports bad flags itself.
+	if flags.Parse(args) != nil || *network != "unix" || *address == "" {
+		return
+	}
+
+	_ = os.Remove(*address)
 }
 
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix("")
 
-	var (
-		address = flag.String("address", "", "Address to listen on (e.g., /tmp/plugin.sock or localhost:50051)")
-		network = flag.String("network", "unix", "Network type: 'unix' or 'tcp'")
-	)
-	flag.Parse()
-
-	if *address == "" {
-		log.Fatal("--address is required")
-	}
-
-	// Setup listener.
-	var listener net.Listener
-	var err error
-
-	if *network == "unix" {
-		// Clean up existing socket if it exists.
-		_ = os.Remove(*address)
-		listener, err = net.Listen("unix", *address)
-	} else {
-		listener, err = net.Listen("tcp", *address)
-	}
-
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-
-	grpcServer := grpc.NewServer()
-	pb.RegisterPluginServer(grpcServer, &headerTransformerPlugin{})
-
-	// Handle graceful shutdown.
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-sigChan
-		log.Println("Shutting down...")
-		grpcServer.GracefulStop()
-		if *network == "unix" {
-			_ = os.Remove(*address)
-		}
-	}()
+	removeStaleSocket(os.Args[1:])
 
-	log.Printf("header-transformer plugin listening on %s (%s)", *address, *network)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	if err := pb.Serve(newHeaderTransformerPlugin()); err != nil {
+		log.Fatal(err)
 	}
 }
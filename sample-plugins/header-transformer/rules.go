@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	pb "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+)
+
+// ruleConfig is the JSON form of an entry in the "rules" custom config value.
+type ruleConfig struct {
+	Name       string            `json:"name"`
+	Methods    []string          `json:"methods"`
+	PathPrefix string            `json:"pathPrefix"`
+	Rename     map[string]string `json:"rename"`
+	Remove     []string          `json:"remove"`
+	Set        map[string]string `json:"set"`
+}
+
+// headerRule applies header changes to requests that match its method and path conditions.
+// Within a rule, renames run first, then removals, then sets.
+type headerRule struct {
+	name       string
+	methods    []string
+	pathPrefix string
+	rename     [][2]string
+	remove     []string
+	set        []headerTemplate
+}
+
+type headerTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// templateData is what set values are rendered with, e.g. "{{.Method}} {{.Path}}" or `{{.Header "User-Agent"}}`.
+type templateData struct {
+	Method     string
+	Path       string
+	RequestURI string
+	RemoteAddr string
+
+	headers map[string]string
+}
+
+// Header returns a header of the original request, matching the name case-insensitively.
+func (d templateData) Header(name string) string {
+	return headerValue(d.headers, name)
+}
+
+// defaultRules reproduce the headers the plugin has always added when no rules are configured.
+const defaultRules = `[{
+	"name": "default",
+	"set": {
+		"X-Transformed-By": "header-transformer-plugin",
+		"X-Original-Path": "{{.Path}}"
+	}
+}]`
+
+func parseRules(raw string) ([]headerRule, error) {
+	var cfgs []ruleConfig
+	if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	rules := make([]headerRule, 0, len(cfgs))
+	for i, c := range cfgs {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i)
+		}
+
+		r := headerRule{name: name, pathPrefix: c.PathPrefix}
+		for _, m := range c.Methods {
+			r.methods = append(r.methods, strings.ToUpper(strings.TrimSpace(m)))
+		}
+
+		// Maps are applied in a fixed order so the result doesn't depend on iteration order.
+		for _, from := range sortedKeys(c.Rename) {
+			to := c.Rename[from]
+			if err := validateHeaderName(to); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			r.rename = append(r.rename, [2]string{from, to})
+		}
+
+		r.remove = c.Remove
+
+		for _, header := range sortedKeys(c.Set) {
+			if err := validateHeaderName(header); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			tmpl, err := template.New(header).Parse(c.Set[header])
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid value template for %s: %w", name, header, err)
+			}
+			r.set = append(r.set, headerTemplate{name: header, tmpl: tmpl})
+		}
+
+		if len(r.rename) == 0 && len(r.remove) == 0 && len(r.set) == 0 {
+			return nil, fmt.Errorf("%s: rule has no rename, remove or set actions", name)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func (r *headerRule) matches(req *pb.HTTPRequest) bool {
+	if len(r.methods) > 0 && !slices.Contains(r.methods, strings.ToUpper(req.Method)) {
+		return false
+	}
+
+	return strings.HasPrefix(req.Path, r.pathPrefix)
+}
+
+// apply changes headers in place. Templates see the headers of the original request.
+func (r *headerRule) apply(req *pb.HTTPRequest, original map[string]string, headers map[string]string) error {
+	for _, rn := range r.rename {
+		if v, ok := takeHeader(headers, rn[0]); ok {
+			deleteHeader(headers, rn[1])
+			headers[rn[1]] = v
+		}
+	}
+
+	for _, name := range r.remove {
+		deleteHeader(headers, name)
+	}
+
+	data := templateData{
+		Method:     req.Method,
+		Path:       req.Path,
+		RequestURI: req.RequestUri,
+		RemoteAddr: req.RemoteAddr,
+		headers:    original,
+	}
+
+	for _, s := range r.set {
+		var buf bytes.Buffer
+		if err := s.tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("%s: failed to render %s: %w", r.name, s.name, err)
+		}
+
+		// Header values can't carry line breaks.
+		value := strings.NewReplacer("\r", "", "\n", " ").Replace(buf.String())
+		deleteHeader(headers, s.name)
+		headers[s.name] = value
+	}
+
+	return nil
+}
+
+func validateHeaderName(name string) error {
+	if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+		return fmt.Errorf("invalid header name %q", name)
+	}
+
+	return nil
+}
+
+// takeHeader removes a header using a case-insensitive name lookup and returns its value.
+func takeHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+			return v, true
+		}
+	}
+
+	return "", false
+}
+
+// deleteHeader removes every case variant of a header.
+func deleteHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
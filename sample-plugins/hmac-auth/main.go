@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultSignatureHeader = "X-Signature"
+	defaultTimestampHeader = "X-Signature-Timestamp"
+	defaultMaxSkew         = 5 * time.Minute
+	defaultReplayCacheSize = 10000
+)
+
+// HMACAuthPlugin authenticates requests signed with a shared secret, as sent by webhook-style integrations.
+type HMACAuthPlugin struct {
+	pluginv1.BasePlugin
+
+	mu              sync.RWMutex
+	verifier        *verifier
+	signatureHeader string
+	timestampHeader string
+	initialized     bool
+}
+
+func newHMACAuthPlugin() *HMACAuthPlugin {
+	return &HMACAuthPlugin{
+		signatureHeader: defaultSignatureHeader,
+		timestampHeader: defaultTimestampHeader,
+	}
+}
+
+func (p *HMACAuthPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "hmac-auth",
+		Version:     "1.0.0",
+		Description: "Verifies HMAC-SHA256 request signatures with timestamp and replay checks",
+	}, nil
+}
+
+func (p *HMACAuthPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *HMACAuthPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	v, err := newVerifier(custom)
+	if err != nil {
+		return nil, fmt.Errorf("hmac auth plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.verifier = v
+	p.signatureHeader = valueOrDefault(custom["signature_header"], defaultSignatureHeader)
+	p.timestampHeader = valueOrDefault(custom["timestamp_header"], defaultTimestampHeader)
+	p.initialized = true
+
+	log.Printf("HMAC auth plugin initialized (max skew: %s, replay cache size: %d)", v.maxSkew, v.seen.max)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *HMACAuthPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("HMAC auth plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.verifier = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *HMACAuthPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("hmac auth plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *HMACAuthPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("hmac auth plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *HMACAuthPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*pluginv1.HTTPResponse, error) {
+	log.Printf("HMAC auth handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("hmac auth plugin not initialized")
+	}
+
+	requestURI := req.RequestUri
+	if requestURI == "" {
+		requestURI = req.Path
+	}
+
+	err := p.verifier.verify(
+		headerValue(req.Headers, p.signatureHeader),
+		headerValue(req.Headers, p.timestampHeader),
+		req.Method,
+		requestURI,
+		req.Body,
+	)
+	if err != nil {
+		log.Printf("HMAC auth rejected request %s %s: %v", req.Method, req.Path, err)
+		return unauthorized(err.Error()), nil
+	}
+
+	return &pluginv1.HTTPResponse{Continue: true}, nil
+}
+
+func newVerifier(custom map[string]string) (*verifier, error) {
+	secret := custom["secret"]
+	if secret == "" {
+		return nil, errors.New("secret is required")
+	}
+
+	v := &verifier{
+		secret:  []byte(secret),
+		maxSkew: defaultMaxSkew,
+	}
+
+	if raw, exists := custom["max_skew"]; exists {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid max_skew %q", raw)
+		}
+		v.maxSkew = d
+	}
+
+	size := defaultReplayCacheSize
+	if raw, exists := custom["replay_cache_size"]; exists {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid replay_cache_size %q", raw)
+		}
+		size = n
+	}
+	v.seen = newReplayCache(size)
+
+	return v, nil
+}
+
+// unauthorized builds a 401 response. The reason is safe to return, since it never includes the expected
+// signature.
+func unauthorized(reason string) *pluginv1.HTTPResponse {
+	body, _ := json.Marshal(map[string]string{"error": "Unauthorized", "message": reason})
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: http.StatusUnauthorized,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: body,
+	}
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newHMACAuthPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// signaturePrefix is the optional scheme prefix on signatures, as used by GitHub-style webhooks.
+const signaturePrefix = "sha256="
+
+// verifier checks request signatures against a shared secret.
+type verifier struct {
+	secret  []byte
+	maxSkew time.Duration
+	seen    *replayCache
+}
+
+// sign computes the signature of a request: an HMAC-SHA256 over the Unix timestamp, method, request URI (path
+// and raw query) and raw body, separated by newlines. Signing the timestamp means a captured signature can't be
+// reused with a fresh one, and signing the query means its parameters can't be changed.
+func (v *verifier) sign(timestamp string, method string, requestURI string, body []byte) []byte {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(timestamp + "\n" + strings.ToUpper(method) + "\n" + requestURI + "\n"))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
+
+// verify checks the signature and timestamp of a request and records the signature so it can't be replayed.
+func (v *verifier) verify(signature string, timestamp string, method string, requestURI string, body []byte) error {
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	if timestamp == "" {
+		return errors.New("missing timestamp")
+	}
+
+	secs, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+
+	now := time.Now()
+	signedAt := time.Unix(secs, 0)
+	if skew := now.Sub(signedAt).Abs(); skew > v.maxSkew {
+		return fmt.Errorf("timestamp is %s outside the allowed skew", (skew - v.maxSkew).Round(time.Second))
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), signaturePrefix))
+	if err != nil {
+		return errors.New("signature is not hex encoded")
+	}
+
+	if !hmac.Equal(got, v.sign(timestamp, method, requestURI, body)) {
+		return errors.New("signature mismatch")
+	}
+
+	// A signature is only accepted while its timestamp is within the skew window, so it only needs to be
+	// remembered until then.
+	return v.seen.add(hex.EncodeToString(got), signedAt.Add(v.maxSkew), now)
+}
+
+// replayCache remembers accepted signatures until they expire.
+type replayCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	max     int
+}
+
+func newReplayCache(maxEntries int) *replayCache {
+	return &replayCache{
+		entries: make(map[string]time.Time),
+		max:     maxEntries,
+	}
+}
+
+// add records a signature, failing if it has been seen before. Expired entries are swept when the cache is
+// full; if it's still full the signature is refused, since accepting it would mean it could be replayed.
+func (c *replayCache) add(key string, expires time.Time, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if exp, exists := c.entries[key]; exists && now.Before(exp) {
+		return errors.New("signature already used")
+	}
+
+	if len(c.entries) >= c.max {
+		for k, exp := range c.entries {
+			if !now.Before(exp) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.max {
+			return errors.New("replay cache is full")
+		}
+	}
+
+	c.entries[key] = expires
+
+	return nil
+}
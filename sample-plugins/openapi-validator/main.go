@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const pluginName = "openapi-validator"
+
+// OpenAPIValidatorPlugin rejects requests that don't match an OpenAPI 3 spec.
+type OpenAPIValidatorPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	validator   *specValidator
+	initialized bool
+}
+
+// validationRejection is the JSON body returned for requests that fail validation.
+type validationRejection struct {
+	Error      string      `json:"error"`
+	Plugin     string      `json:"plugin"`
+	Operation  string      `json:"operation,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	Violations []violation `json:"violations,omitempty"`
+}
+
+func newOpenAPIValidatorPlugin() *OpenAPIValidatorPlugin {
+	return &OpenAPIValidatorPlugin{}
+}
+
+func (p *OpenAPIValidatorPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        pluginName,
+		Version:     "1.0.0",
+		Description: "Validates request parameters, headers and bodies against an OpenAPI 3 spec",
+	}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) GetCapabilities(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	specFile := custom["spec_file"]
+	if specFile == "" {
+		return nil, errors.New("openapi validator plugin configuration failed: spec_file is required")
+	}
+
+	opts := validatorOptions{basePath: custom["base_path"]}
+	var err error
+	if opts.rejectUnknownOperations, err = boolOption(custom, "reject_unknown_operations", false); err != nil {
+		return nil, fmt.Errorf("openapi validator plugin configuration failed: %w", err)
+	}
+	if opts.rejectUnknownQueryParams, err = boolOption(custom, "reject_unknown_query_params", false); err != nil {
+		return nil, fmt.Errorf("openapi validator plugin configuration failed: %w", err)
+	}
+	if opts.validateBody, err = boolOption(custom, "validate_body", true); err != nil {
+		return nil, fmt.Errorf("openapi validator plugin configuration failed: %w", err)
+	}
+
+	v, err := newSpecValidator(ctx, specFile, opts)
+	if err != nil {
+		return nil, fmt.Errorf("openapi validator plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.validator = v
+	p.initialized = true
+
+	log.Printf(
+		"OpenAPI validator plugin initialized from %s (%s %s, %d paths)",
+		specFile,
+		v.doc.Info.Title,
+		v.doc.Info.Version,
+		v.doc.Paths.Len(),
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("OpenAPI validator plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.validator = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("openapi validator plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("openapi validator plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *OpenAPIValidatorPlugin) HandleRequest(
+	ctx context.Context,
+	req *pluginv1.HTTPRequest,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("OpenAPI validator handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	v := p.validator
+	p.mu.RUnlock()
+
+	if v == nil {
+		return nil, fmt.Errorf("openapi validator plugin not initialized")
+	}
+
+	httpReq, err := newHTTPRequest(ctx, req)
+	if err != nil {
+		return reject(http.StatusBadRequest, validationRejection{
+			Error:   "Request validation failed",
+			Message: fmt.Sprintf("malformed request: %v", err),
+		})
+	}
+
+	// A compressed body can't be validated, and skipping it would let any client bypass body validation by
+	// adding the header.
+	if v.options.validateBody && len(req.Body) > 0 && isEncoded(httpReq.Header) {
+		log.Printf("OpenAPI validator refused %s %s with Content-Encoding %q",
+			req.Method, req.Path, httpReq.Header.Get("Content-Encoding"))
+		return reject(http.StatusUnsupportedMediaType, validationRejection{
+			Error:   "Request validation failed",
+			Message: "encoded request bodies can't be validated",
+		})
+	}
+
+	operation, violations, err := v.validate(ctx, httpReq)
+
+	var unknown *unknownOperationError
+	if errors.As(err, &unknown) {
+		log.Printf("OpenAPI validator rejected %s %s: %v", req.Method, req.Path, unknown)
+		return reject(unknown.status, validationRejection{
+			Error:   "Unknown operation",
+			Message: fmt.Sprintf("%s %s: %v", req.Method, req.Path, unknown),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	if len(violations) == 0 {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	log.Printf("OpenAPI validator rejected %s (%d violations)", operation, len(violations))
+
+	return reject(http.StatusBadRequest, validationRejection{
+		Error:      "Request validation failed",
+		Operation:  operation,
+		Violations: violations,
+	})
+}
+
+func reject(status int, rejection validationRejection) (*pluginv1.HTTPResponse, error) {
+	rejection.Plugin = pluginName
+
+	body, err := json.Marshal(rejection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rejection: %w", err)
+	}
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: int32(status),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: body,
+	}, nil
+}
+
+func boolOption(custom map[string]string, key string, def bool) (bool, error) {
+	raw, exists := custom[key]
+	if !exists {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", key, raw)
+	}
+
+	return b, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newOpenAPIValidatorPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+)
+
+// validatorOptions are the strictness settings from CustomConfig.
+type validatorOptions struct {
+	basePath                 string
+	rejectUnknownOperations  bool
+	rejectUnknownQueryParams bool
+	validateBody             bool
+}
+
+// specValidator validates requests against a loaded OpenAPI 3 document.
+type specValidator struct {
+	doc     *openapi3.T
+	router  routers.Router
+	options validatorOptions
+}
+
+// violation is one reason a request doesn't match the spec.
+type violation struct {
+	In      string `json:"in"`
+	Name    string `json:"name,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+// unknownOperationError is returned when no operation in the spec matches the request.
+type unknownOperationError struct {
+	status int
+	err    error
+}
+
+func (e *unknownOperationError) Error() string {
+	return e.err.Error()
+}
+
+func newSpecValidator(ctx context.Context, specFile string, opts validatorOptions) (*specValidator, error) {
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	// The spec comes from the operator's config, so references to files next to it are trusted.
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := loader.LoadFromFile(specFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spec %s: %w", specFile, err)
+	}
+
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", specFile, err)
+	}
+
+	// The spec's servers usually name the upstream's own host, which never matches requests arriving at mcpd,
+	// so routes are matched on base_path alone.
+	doc.Servers = openapi3.Servers{{URL: "/" + strings.Trim(opts.basePath, "/")}}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build routes from spec %s: %w", specFile, err)
+	}
+
+	return &specValidator{doc: doc, router: router, options: opts}, nil
+}
+
+// validate checks a request against the spec. It returns the operation name and any violations, or an
+// *unknownOperationError when the request matches no operation and unknown operations are rejected.
+func (v *specValidator) validate(ctx context.Context, httpReq *http.Request) (string, []violation, error) {
+	route, pathParams, err := v.router.FindRoute(httpReq)
+	if err != nil {
+		if !v.options.rejectUnknownOperations {
+			return "", nil, nil
+		}
+
+		status := http.StatusNotFound
+		if errors.Is(err, routers.ErrMethodNotAllowed) {
+			status = http.StatusMethodNotAllowed
+		}
+
+		return "", nil, &unknownOperationError{status: status, err: err}
+	}
+
+	operation := route.Operation.OperationID
+	if operation == "" {
+		operation = route.Method + " " + route.Path
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    httpReq,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			ExcludeRequestBody: !v.options.validateBody,
+			MultiError:         true,
+			// Authentication is the job of the authn plugins; only the shape of the request is checked here.
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			SkipSettingDefaults: true,
+		},
+	}
+
+	var violations []violation
+	if err := openapi3filter.ValidateRequest(ctx, input); err != nil {
+		violations = flatten(err, violation{})
+	}
+
+	if v.options.rejectUnknownQueryParams {
+		violations = append(violations, unknownQueryParams(route, httpReq)...)
+	}
+
+	return operation, violations, nil
+}
+
+// flatten turns the nested errors from openapi3filter into a list of violations, carrying the parameter or
+// body context down to the schema errors beneath it. It matches concrete types rather than using errors.As,
+// since both RequestError and MultiError unwrap into each other and would lose that context.
+func flatten(err error, ctx violation) []violation {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var out []violation
+		for _, inner := range e {
+			out = append(out, flatten(inner, ctx)...)
+		}
+		return out
+
+	case *openapi3filter.RequestError:
+		switch {
+		case e.Parameter != nil:
+			ctx.In, ctx.Name = e.Parameter.In, e.Parameter.Name
+		case e.RequestBody != nil:
+			ctx.In = "body"
+		}
+
+		switch e.Err.(type) {
+		case openapi3.MultiError, *openapi3.SchemaError:
+			return flatten(e.Err, ctx)
+		}
+
+		ctx.Message = e.Reason
+		if e.Err != nil {
+			if ctx.Message == "" {
+				ctx.Message = e.Err.Error()
+			} else if ctx.Message != e.Err.Error() {
+				ctx.Message += ": " + e.Err.Error()
+			}
+		}
+
+	case *openapi3.SchemaError:
+		if ptr := e.JSONPointer(); len(ptr) > 0 {
+			ctx.Pointer = "/" + strings.Join(ptr, "/")
+		}
+		ctx.Message = e.Reason
+
+	default:
+		ctx.Message = err.Error()
+	}
+
+	if ctx.In == "" {
+		ctx.In = "request"
+	}
+
+	return []violation{ctx}
+}
+
+// unknownQueryParams reports query parameters that neither the operation nor its path declare.
+func unknownQueryParams(route *routers.Route, httpReq *http.Request) []violation {
+	declared := make(map[string]bool)
+	for _, params := range []openapi3.Parameters{route.PathItem.Parameters, route.Operation.Parameters} {
+		for _, ref := range params {
+			if p := ref.Value; p != nil && p.In == openapi3.ParameterInQuery {
+				declared[p.Name] = true
+			}
+		}
+	}
+
+	var out []violation
+	for _, name := range slices.Sorted(maps.Keys(httpReq.URL.Query())) {
+		if !declared[name] {
+			out = append(out, violation{In: openapi3.ParameterInQuery, Name: name, Message: "unknown query parameter"})
+		}
+	}
+
+	return out
+}
+
+// newHTTPRequest rebuilds enough of an http.Request for routing and validation.
+func newHTTPRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*http.Request, error) {
+	uri := req.RequestUri
+	if uri == "" {
+		uri = req.Path
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, uri, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	return httpReq, nil
+}
+
+// isEncoded reports whether the body has a Content-Encoding other than identity, which isn't decoded here.
+func isEncoded(h http.Header) bool {
+	enc := strings.TrimSpace(h.Get("Content-Encoding"))
+	return enc != "" && !strings.EqualFold(enc, "identity")
+}
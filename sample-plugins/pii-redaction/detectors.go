@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	detectorEmail      = "email"
+	detectorSSN        = "ssn"
+	detectorCreditCard = "credit_card"
+)
+
+// builtinPatterns are the detectors enabled by default.
+var builtinPatterns = map[string]string{
+	detectorEmail: `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`,
+	// Excludes the area, group and serial numbers the SSA never issues.
+	detectorSSN: `\b(?:00[1-9]|0[1-9]\d|[1-578]\d\d|6[0-57-9]\d|66[0-57-9])` +
+		`-(?:0[1-9]|[1-9]\d)-(?:000[1-9]|00[1-9]\d|0[1-9]\d\d|[1-9]\d{3})\b`,
+	detectorCreditCard: `\b(?:\d[ -]?){12,18}\d\b`,
+}
+
+// patternConfig is the JSON form of an entry in the "patterns" custom config value.
+type patternConfig struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+// detector finds one kind of PII in text.
+type detector struct {
+	name  string
+	re    *regexp.Regexp
+	valid func(string) bool
+}
+
+// finding is the number of matches for a detector. Matched values are never recorded.
+type finding struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// newDetectors builds the enabled built-in detectors followed by the custom patterns.
+func newDetectors(enabled string, patterns string) ([]detector, error) {
+	names := []string{detectorEmail, detectorSSN, detectorCreditCard}
+	if enabled != "" {
+		names = names[:0]
+		for _, n := range strings.Split(enabled, ",") {
+			if n = strings.ToLower(strings.TrimSpace(n)); n != "" && n != "none" {
+				names = append(names, n)
+			}
+		}
+	}
+
+	var detectors []detector
+	for _, n := range names {
+		expr, ok := builtinPatterns[n]
+		if !ok {
+			return nil, fmt.Errorf("unknown detector %q", n)
+		}
+
+		d := detector{name: n, re: regexp.MustCompile(expr)}
+		if n == detectorCreditCard {
+			d.valid = luhnValid
+		}
+		detectors = append(detectors, d)
+	}
+
+	if patterns == "" {
+		return detectors, nil
+	}
+
+	var cfgs []patternConfig
+	if err := json.Unmarshal([]byte(patterns), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid patterns: %w", err)
+	}
+
+	for i, c := range cfgs {
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("pattern-%d", i)
+		}
+
+		re, err := regexp.Compile(c.Regex)
+		if err != nil || c.Regex == "" {
+			return nil, fmt.Errorf("pattern %q: invalid regex %q", c.Name, c.Regex)
+		}
+
+		detectors = append(detectors, detector{name: c.Name, re: re})
+	}
+
+	return detectors, nil
+}
+
+// redact replaces every match in text with the mask, where "{type}" expands to the detector name, and adds
+// the number of matches of each detector to counts.
+func redact(detectors []detector, text string, replacement string, counts []int) string {
+	for i, d := range detectors {
+		r := strings.ReplaceAll(replacement, "{type}", d.name)
+		text = d.re.ReplaceAllStringFunc(text, func(m string) string {
+			if d.valid != nil && !d.valid(m) {
+				return m
+			}
+			counts[i]++
+			return r
+		})
+	}
+
+	return text
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum, filtering out order numbers and the like.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+
+	return n >= 13 && sum%10 == 0
+}
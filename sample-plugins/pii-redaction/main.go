@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	policyLog   = "log"
+	policyMask  = "mask"
+	policyBlock = "block"
+
+	defaultMaskValue = "[REDACTED:{type}]"
+)
+
+// blockBody is the JSON body returned in place of a blocked response.
+type blockBody struct {
+	Error    string    `json:"error"`
+	Message  string    `json:"message"`
+	Findings []finding `json:"findings"`
+}
+
+// PIIRedactionPlugin scans response bodies for PII and logs, masks or blocks them.
+type PIIRedactionPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	detectors   []detector
+	policy      string
+	maskValue   string
+	initialized bool
+}
+
+func newPIIRedactionPlugin() *PIIRedactionPlugin {
+	return &PIIRedactionPlugin{
+		policy:    policyMask,
+		maskValue: defaultMaskValue,
+	}
+}
+
+func (p *PIIRedactionPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "pii-redaction",
+		Version:     "1.0.0",
+		Description: "Detects PII in response bodies and logs, masks or blocks it",
+	}, nil
+}
+
+func (p *PIIRedactionPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowResponse},
+	}, nil
+}
+
+func (p *PIIRedactionPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	detectors, err := newDetectors(custom["detectors"], custom["patterns"])
+	if err != nil {
+		return nil, fmt.Errorf("pii redaction plugin configuration failed: %w", err)
+	}
+
+	policy := strings.ToLower(valueOrDefault(custom["policy"], policyMask))
+	if policy != policyLog && policy != policyMask && policy != policyBlock {
+		return nil, fmt.Errorf("pii redaction plugin configuration failed: invalid policy %q", policy)
+	}
+
+	// JSON is re-encoded after masking, but bodies that don't parse are masked as raw text, so a mask must
+	// still not be able to break out of a JSON string.
+	maskValue := valueOrDefault(custom["mask_value"], defaultMaskValue)
+	for _, d := range detectors {
+		m := strings.ReplaceAll(maskValue, "{type}", d.name)
+		if strings.ContainsAny(m, "\"\\") || strings.ContainsFunc(m, isControl) {
+			return nil, fmt.Errorf("pii redaction plugin configuration failed: invalid mask for %q: %q", d.name, m)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.detectors = detectors
+	p.policy = policy
+	p.maskValue = maskValue
+	p.initialized = true
+
+	log.Printf("PII redaction plugin initialized with %d detectors (policy: %s)", len(detectors), policy)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PIIRedactionPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("PII redaction plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.detectors = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PIIRedactionPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("pii redaction plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PIIRedactionPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("pii redaction plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PIIRedactionPlugin) HandleResponse(
+	ctx context.Context,
+	resp *pluginv1.HTTPResponse,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("PII redaction handling response: %d", resp.StatusCode)
+
+	p.mu.RLock()
+	detectors, policy, maskValue := p.detectors, p.policy, p.maskValue
+	p.mu.RUnlock()
+
+	if len(detectors) == 0 || len(resp.Body) == 0 || !isScannable(resp.Headers) {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	findings, masked := redactBody(detectors, resp.Body, mediaType(resp.Headers), maskValue)
+	if len(findings) == 0 {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	// Only counts are logged; the matched values would leak the PII into the logs.
+	log.Printf("PII redaction found %s in response (policy: %s)", summarize(findings), policy)
+
+	switch policy {
+	case policyBlock:
+		body, err := json.Marshal(blockBody{
+			Error:    "Bad Gateway",
+			Message:  "response blocked: contains personally identifiable information",
+			Findings: findings,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode block response: %w", err)
+		}
+
+		return &pluginv1.HTTPResponse{
+			Continue:   false,
+			StatusCode: 502,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+			},
+			Body: body,
+		}, nil
+	case policyMask:
+		headers := maps.Clone(resp.Headers)
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		deleteHeader(headers, "Content-Length")
+		headers["Content-Length"] = strconv.Itoa(len(masked))
+
+		return &pluginv1.HTTPResponse{
+			Continue:   true,
+			StatusCode: resp.StatusCode,
+			Headers:    headers,
+			Body:       masked,
+		}, nil
+	default:
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+}
+
+// isScannable reports whether the body is uncompressed text worth scanning.
+func isScannable(headers map[string]string) bool {
+	if enc := strings.ToLower(headerValue(headers, "Content-Encoding")); enc != "" && enc != "identity" {
+		return false
+	}
+
+	mt := mediaType(headers)
+
+	return mt == "" ||
+		strings.HasPrefix(mt, "text/") ||
+		strings.HasSuffix(mt, "json") ||
+		strings.HasSuffix(mt, "xml")
+}
+
+// mediaType returns the lower-cased Content-Type without parameters.
+func mediaType(headers map[string]string) string {
+	mt, _, _ := strings.Cut(strings.ToLower(headerValue(headers, "Content-Type")), ";")
+
+	return strings.TrimSpace(mt)
+}
+
+func summarize(findings []finding) string {
+	parts := make([]string, 0, len(findings))
+	for _, f := range findings {
+		parts = append(parts, fmt.Sprintf("%s=%d", f.Type, f.Count))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// deleteHeader removes every case variant of a header.
+func deleteHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
+
+func valueOrDefault(v string, def string) string {
+	if v == "" {
+		return def
+	}
+
+	return v
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newPIIRedactionPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// redactBody masks PII in a response body and reports what it found, in detector order.
+//
+// JSON bodies, and the JSON payloads of SSE data lines, are decoded so that only string values are scanned
+// and masked: replacing a bare number that happens to match a pattern (an epoch-millisecond timestamp passes
+// the card checks, for example) or a match that spans quotes would leave invalid JSON. Other text is masked
+// as raw bytes.
+func redactBody(detectors []detector, body []byte, mediaType string, replacement string) ([]finding, []byte) {
+	counts := make([]int, len(detectors))
+
+	var out []byte
+	switch {
+	case isJSONMediaType(mediaType):
+		out = redactJSONOrText(detectors, body, replacement, counts)
+	case mediaType == "text/event-stream":
+		out = redactEventStream(detectors, body, replacement, counts)
+	default:
+		out = []byte(redact(detectors, string(body), replacement, counts))
+	}
+
+	var findings []finding
+	for i, d := range detectors {
+		if counts[i] > 0 {
+			findings = append(findings, finding{Type: d.name, Count: counts[i]})
+		}
+	}
+
+	return findings, out
+}
+
+// redactJSONOrText redacts the string values of a JSON document, falling back to raw masking when the body
+// doesn't parse.
+func redactJSONOrText(detectors []detector, body []byte, replacement string, counts []int) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil || dec.More() {
+		return []byte(redact(detectors, string(body), replacement, counts))
+	}
+
+	doc = redactValue(detectors, doc, replacement, counts)
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return []byte(redact(detectors, string(body), replacement, counts))
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactEventStream redacts the payload of each SSE data line as JSON where it parses, and as text otherwise.
+func redactEventStream(detectors []detector, body []byte, replacement string, counts []int) []byte {
+	lines := strings.Split(string(body), "\n")
+	for i, line := range lines {
+		payload, found := strings.CutPrefix(line, "data:")
+		if !found {
+			lines[i] = redact(detectors, line, replacement, counts)
+			continue
+		}
+
+		// Keep the line's own prefix and line ending so untouched framing stays byte for byte the same.
+		prefix, suffix := "data:", ""
+		if strings.HasPrefix(payload, " ") {
+			prefix, payload = "data: ", payload[1:]
+		}
+		if strings.HasSuffix(payload, "\r") {
+			suffix, payload = "\r", payload[:len(payload)-1]
+		}
+
+		lines[i] = prefix + string(redactJSONOrText(detectors, []byte(payload), replacement, counts)) + suffix
+	}
+
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// redactValue masks every string value in a decoded JSON document. Object keys are left alone.
+func redactValue(detectors []detector, v any, replacement string, counts []int) any {
+	switch t := v.(type) {
+	case string:
+		return redact(detectors, t, replacement, counts)
+	case []any:
+		for i := range t {
+			t[i] = redactValue(detectors, t[i], replacement, counts)
+		}
+	case map[string]any:
+		for k := range t {
+			t[k] = redactValue(detectors, t[k], replacement, counts)
+		}
+	}
+
+	return v
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
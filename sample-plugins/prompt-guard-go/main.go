@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const pluginName = "prompt-guard-go"
+
+// PromptGuardPlugin rejects requests whose JSON bodies look like prompt-injection attempts.
+type PromptGuardPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	rules       *ruleSet
+	initialized bool
+}
+
+// rejection is the JSON body returned for blocked requests.
+type rejection struct {
+	Error      string      `json:"error"`
+	Plugin     string      `json:"plugin"`
+	Score      float64     `json:"score,omitempty"`
+	Violations []violation `json:"violations,omitempty"`
+}
+
+func newPromptGuardPlugin() *PromptGuardPlugin {
+	return &PromptGuardPlugin{}
+}
+
+func (p *PromptGuardPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        pluginName,
+		Version:     "1.0.0",
+		Description: "Screens request JSON bodies for prompt-injection phrases, patterns and keywords",
+	}, nil
+}
+
+func (p *PromptGuardPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *PromptGuardPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	rules, err := newRuleSet(cfg.GetCustomConfig())
+	if err != nil {
+		return nil, fmt.Errorf("prompt guard plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = rules
+	p.initialized = true
+
+	log.Printf(
+		"Prompt guard plugin initialized (phrases: %d, patterns: %d, keywords: %d, threshold: %g)",
+		len(rules.phrases),
+		len(rules.patterns),
+		len(rules.keywords),
+		rules.threshold,
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PromptGuardPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Prompt guard plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.rules = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PromptGuardPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("prompt guard plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PromptGuardPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("prompt guard plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *PromptGuardPlugin) HandleRequest(
+	ctx context.Context,
+	req *pluginv1.HTTPRequest,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("Prompt guard handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	rules := p.rules
+	p.mu.RUnlock()
+
+	if rules == nil || !rules.enabled() || len(req.Body) == 0 {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	// A compressed body can't be screened, and letting it through would let any client skip the scan by
+	// adding the header, so it is refused instead.
+	if isEncoded(req.Headers) {
+		log.Printf("Prompt guard refused request with Content-Encoding %q", headerValue(req.Headers, "Content-Encoding"))
+		return reject(http.StatusUnsupportedMediaType, rejection{
+			Error: "Request blocked: encoded request bodies can't be screened",
+		})
+	}
+
+	// Like the C# plugin, try JSON whatever the Content-Type says, so a missing or wrong header can't skip
+	// the scan. Anything else that is text is screened as a single string.
+	var doc any
+	if err := json.Unmarshal(req.Body, &doc); err != nil {
+		if !utf8.Valid(req.Body) {
+			return &pluginv1.HTTPResponse{Continue: true}, nil
+		}
+		doc = string(req.Body)
+	}
+
+	violations, score, blocked := rules.screen(doc)
+	if !blocked {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	log.Printf("Prompt guard blocked request: %d violations, keyword score %g", len(violations), score)
+
+	return reject(http.StatusBadRequest, rejection{
+		Error:      "Request blocked: prohibited content detected",
+		Score:      score,
+		Violations: violations,
+	})
+}
+
+func reject(status int, r rejection) (*pluginv1.HTTPResponse, error) {
+	r.Plugin = pluginName
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode rejection: %w", err)
+	}
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: int32(status),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: body,
+	}, nil
+}
+
+// isEncoded reports whether the body has a Content-Encoding other than identity.
+func isEncoded(headers map[string]string) bool {
+	enc := strings.TrimSpace(headerValue(headers, "Content-Encoding"))
+	return enc != "" && !strings.EqualFold(enc, "identity")
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newPromptGuardPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
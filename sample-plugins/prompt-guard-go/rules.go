@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Violation types reported in rejection bodies.
+const (
+	violationPhrase  = "phrase"
+	violationPattern = "pattern"
+	violationKeyword = "keyword"
+)
+
+// defaultBlockedPhrases match the phrases used by the C# prompt-guard sample.
+var defaultBlockedPhrases = []string{
+	"naughty naughty very naughty",
+	"ignore previous instructions",
+	"disregard",
+	"system prompt",
+	"you are now",
+}
+
+// defaultThreshold is the keyword score at which a request is blocked.
+const defaultThreshold = 1.0
+
+// patternConfig is the JSON form of an entry in the "patterns" custom config value.
+type patternConfig struct {
+	Name  string `json:"name"`
+	Regex string `json:"regex"`
+}
+
+type pattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// violation is a rule that matched the request. The matched text itself is never echoed back.
+type violation struct {
+	Type   string  `json:"type"`
+	Rule   string  `json:"rule"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// ruleSet screens text for prompt-injection attempts.
+//
+// Blocked phrases and regex patterns block on any match. Keywords carry weights that are summed,
+// once per keyword, across the whole request; the request is blocked when the score reaches the threshold.
+type ruleSet struct {
+	phrases   []string
+	patterns  []pattern
+	keywords  map[string]float64
+	threshold float64
+}
+
+func newRuleSet(custom map[string]string) (*ruleSet, error) {
+	rs := &ruleSet{
+		phrases:   defaultBlockedPhrases,
+		threshold: defaultThreshold,
+	}
+
+	if v, exists := custom["blocked_phrases"]; exists {
+		rs.phrases = nil
+		for _, phrase := range strings.Split(v, ",") {
+			if phrase = normalize(phrase); phrase != "" {
+				rs.phrases = append(rs.phrases, phrase)
+			}
+		}
+	}
+
+	if v := custom["patterns"]; v != "" {
+		var cfgs []patternConfig
+		if err := json.Unmarshal([]byte(v), &cfgs); err != nil {
+			return nil, fmt.Errorf("invalid patterns: %w", err)
+		}
+
+		for i, c := range cfgs {
+			if c.Name == "" {
+				return nil, fmt.Errorf("pattern %d has no name", i)
+			}
+
+			re, err := regexp.Compile(c.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex for pattern %q: %w", c.Name, err)
+			}
+			rs.patterns = append(rs.patterns, pattern{name: c.Name, re: re})
+		}
+	}
+
+	if v := custom["keywords"]; v != "" {
+		var weights map[string]float64
+		if err := json.Unmarshal([]byte(v), &weights); err != nil {
+			return nil, fmt.Errorf("invalid keywords: expected a JSON object of keyword to weight: %w", err)
+		}
+
+		rs.keywords = make(map[string]float64, len(weights))
+		for k, w := range weights {
+			if w <= 0 {
+				return nil, fmt.Errorf("invalid weight %g for keyword %q: must be positive", w, k)
+			}
+			if k = normalize(k); k != "" {
+				rs.keywords[k] = w
+			}
+		}
+	}
+
+	if v, exists := custom["score_threshold"]; exists {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil || t <= 0 {
+			return nil, fmt.Errorf("invalid score_threshold %q: must be a positive number", v)
+		}
+		rs.threshold = t
+	}
+
+	return rs, nil
+}
+
+func (rs *ruleSet) enabled() bool {
+	return len(rs.phrases) > 0 || len(rs.patterns) > 0 || len(rs.keywords) > 0
+}
+
+// screen checks every string in the document and returns the violations found and the keyword score.
+// The request should be blocked when blocked is true.
+func (rs *ruleSet) screen(doc any) (violations []violation, score float64, blocked bool) {
+	matchedKeywords := make(map[string]bool)
+
+	walkStrings(doc, func(s string) {
+		text := normalize(s)
+
+		for _, phrase := range rs.phrases {
+			if strings.Contains(text, phrase) {
+				violations = appendOnce(violations, violation{Type: violationPhrase, Rule: phrase})
+			}
+		}
+
+		for _, p := range rs.patterns {
+			if p.re.MatchString(s) {
+				violations = appendOnce(violations, violation{Type: violationPattern, Rule: p.name})
+			}
+		}
+
+		for k := range rs.keywords {
+			if strings.Contains(text, k) {
+				matchedKeywords[k] = true
+			}
+		}
+	})
+
+	// Object members are visited in random order; keep the report stable.
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Type != violations[j].Type {
+			return violations[i].Type < violations[j].Type
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+
+	blocked = len(violations) > 0
+
+	keywords := make([]string, 0, len(matchedKeywords))
+	for k := range matchedKeywords {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+
+	for _, k := range keywords {
+		score += rs.keywords[k]
+	}
+
+	if len(keywords) > 0 && score >= rs.threshold {
+		blocked = true
+		for _, k := range keywords {
+			violations = append(violations, violation{Type: violationKeyword, Rule: k, Weight: rs.keywords[k]})
+		}
+	}
+
+	return violations, score, blocked
+}
+
+// walkStrings calls fn for every string value (not key) in a decoded JSON document.
+func walkStrings(v any, fn func(string)) {
+	switch val := v.(type) {
+	case string:
+		fn(val)
+	case []any:
+		for _, item := range val {
+			walkStrings(item, fn)
+		}
+	case map[string]any:
+		for _, item := range val {
+			walkStrings(item, fn)
+		}
+	}
+}
+
+// normalize lower-cases text and collapses runs of whitespace, so "Ignore   previous\ninstructions"
+// matches "ignore previous instructions".
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+func appendOnce(violations []violation, v violation) []violation {
+	for _, existing := range violations {
+		if existing == v {
+			return violations
+		}
+	}
+
+	return append(violations, v)
+}
@@ -4,10 +4,13 @@ go 1.25.1
 
 require (
 	github.com/mozilla-ai/mcpd-plugins-sdk-go v0.0.1
+	github.com/redis/go-redis/v9 v9.14.0
 	google.golang.org/protobuf v1.36.10
 )
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	golang.org/x/net v0.44.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"net/netip"
 	"strconv"
 	"sync"
 	"time"
@@ -16,18 +18,18 @@ import (
 type RateLimitPlugin struct {
 	pluginv1.BasePlugin
 
-	mu          sync.RWMutex
-	requests    map[string]int
-	lastReset   time.Time
-	maxRequests int
-	window      time.Duration
-	initialized bool
+	mu             sync.RWMutex
+	store          counterStore
+	rules          []rateLimitRule
+	trustedProxies []netip.Prefix
+	maxRequests    int
+	window         time.Duration
+	initialized    bool
 }
 
 func newRateLimitPlugin() *RateLimitPlugin {
 	return &RateLimitPlugin{
-		requests:    make(map[string]int),
-		lastReset:   time.Now(),
+		store:       newMemoryStore(),
 		maxRequests: 100,
 		window:      time.Minute,
 	}
@@ -48,27 +50,69 @@ func (p *RateLimitPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty)
 }
 
 func (p *RateLimitPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+	var rules []rateLimitRule
+	if rulesStr, exists := cfg.CustomConfig["rules"]; exists {
+		parsed, err := parseRules(rulesStr)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit plugin configuration failed: %w", err)
+		}
+		rules = parsed
+	}
+
+	trustedProxies, err := parseTrustedProxies(cfg.CustomConfig["trusted_proxies"])
+	if err != nil {
+		return nil, fmt.Errorf("rate limit plugin configuration failed: %w", err)
+	}
 
+	maxRequests := 0
 	if maxReqStr, exists := cfg.CustomConfig["max_requests"]; exists {
-		if maxReq, err := strconv.Atoi(maxReqStr); err == nil && maxReq > 0 {
-			p.maxRequests = maxReq
-			log.Printf("Rate limit max_requests configured to: %d", p.maxRequests)
+		maxReq, err := strconv.Atoi(maxReqStr)
+		if err != nil || maxReq <= 0 {
+			return nil, fmt.Errorf("rate limit plugin configuration failed: invalid max_requests %q", maxReqStr)
 		}
+		maxRequests = maxReq
 	}
 
+	var window time.Duration
 	if windowStr, exists := cfg.CustomConfig["window"]; exists {
-		if window, err := time.ParseDuration(windowStr); err == nil && window > 0 {
-			p.window = window
-			log.Printf("Rate limit window configured to: %v", p.window)
+		d, err := time.ParseDuration(windowStr)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("rate limit plugin configuration failed: invalid window %q", windowStr)
 		}
+		window = d
 	}
 
+	store, err := newStoreFromConfig(cfg.CustomConfig)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	if maxRequests > 0 {
+		p.maxRequests = maxRequests
+	}
+	if window > 0 {
+		p.window = window
+	}
+	previous := p.store
+	p.store = store
+	p.rules = rules
+	p.trustedProxies = trustedProxies
 	p.initialized = true
-	p.lastReset = time.Now()
+	defaultRule := p.defaultRule()
+	p.mu.Unlock()
 
-	log.Printf("Rate limit plugin initialized with limits: %d requests per %v", p.maxRequests, p.window)
+	// Closing a remote store can block on the network, so it happens outside the lock.
+	if err := previous.Close(); err != nil {
+		log.Printf("Rate limit failed to close previous counter store: %v", err)
+	}
+
+	log.Printf("Rate limit plugin initialized with limits: %d requests per %v",
+		defaultRule.maxRequests, defaultRule.window)
+	for _, r := range rules {
+		log.Printf("Rate limit rule %q (path prefix %q, tool %q): %d requests per %v",
+			r.name, r.pathPrefix, r.tool, r.maxRequests, r.window)
+	}
 
 	return &emptypb.Empty{}, nil
 }
@@ -77,10 +121,14 @@ func (p *RateLimitPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.
 	log.Println("Rate limit plugin cleaning up...")
 
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
+	store := p.store
+	p.store = newMemoryStore()
 	p.initialized = false
-	p.requests = make(map[string]int)
+	p.mu.Unlock()
+
+	if err := store.Close(); err != nil {
+		log.Printf("Rate limit failed to close counter store: %v", err)
+	}
 
 	return &emptypb.Empty{}, nil
 }
@@ -110,37 +158,53 @@ func (p *RateLimitPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*em
 func (p *RateLimitPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPRequest) (*pluginv1.HTTPResponse, error) {
 	log.Printf("Rate limit handling request: %s %s", req.Method, req.Path)
 
-	clientID := p.extractClientID(req.Headers)
+	tool := headerValue(req.Headers, toolNameHeader)
+
+	p.mu.RLock()
+	clientID := p.extractClientID(req)
+	store := p.store
+	rule := selectRule(p.rules, p.defaultRule(), req.Path, tool)
+	p.mu.RUnlock()
+
+	maxRequests := rule.maxRequests
+
+	// Each rule counts independently per client.
+	count, resetAt, err := store.Increment(ctx, rule.name+":"+clientID, rule.window)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit check failed: %w", err)
+	}
+
+	if count > int64(maxRequests) {
+		log.Printf("Rate limit exceeded for client: %s, rule: %s", clientID, rule.name)
 
-	if p.isRateLimited(clientID) {
-		log.Printf("Rate limit exceeded for client: %s", clientID)
+		retryAfter := retryAfterSeconds(resetAt)
 
 		return &pluginv1.HTTPResponse{
 			Continue:   false,
 			StatusCode: 429,
 			Headers: map[string]string{
 				"Content-Type":          "application/json",
-				"Retry-After":           "60",
-				"X-RateLimit-Limit":     strconv.Itoa(p.maxRequests),
+				"Retry-After":           strconv.FormatInt(retryAfter, 10),
+				"X-RateLimit-Limit":     strconv.Itoa(maxRequests),
 				"X-RateLimit-Remaining": "0",
-				"X-RateLimit-Reset":     strconv.FormatInt(p.getResetTime(), 10),
+				"X-RateLimit-Reset":     strconv.FormatInt(resetAt.Unix(), 10),
 			},
-			Body: []byte(`{"error": "Rate limit exceeded", "retry_after": 60}`),
+			Body: []byte(fmt.Sprintf(`{"error": "Rate limit exceeded", "retry_after": %d}`, retryAfter)),
 		}, nil
 	}
 
-	remaining := p.incrementRequest(clientID)
+	remaining := int64(maxRequests) - count
 
 	headers := make(map[string]string)
 	for k, v := range req.Headers {
 		headers[k] = v
 	}
 
-	headers["X-RateLimit-Limit"] = strconv.Itoa(p.maxRequests)
-	headers["X-RateLimit-Remaining"] = strconv.Itoa(remaining)
-	headers["X-RateLimit-Reset"] = strconv.FormatInt(p.getResetTime(), 10)
+	headers["X-RateLimit-Limit"] = strconv.Itoa(maxRequests)
+	headers["X-RateLimit-Remaining"] = strconv.FormatInt(remaining, 10)
+	headers["X-RateLimit-Reset"] = strconv.FormatInt(resetAt.Unix(), 10)
 
-	log.Printf("Rate limit passed for client: %s, remaining: %d", clientID, remaining)
+	log.Printf("Rate limit passed for client: %s, rule: %s, remaining: %d", clientID, rule.name, remaining)
 
 	return &pluginv1.HTTPResponse{
 		Continue: true,
@@ -148,53 +212,77 @@ func (p *RateLimitPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPR
 	}, nil
 }
 
-// extractClientID extracts client identifier from request headers.
-func (p *RateLimitPlugin) extractClientID(headers map[string]string) string {
-	if clientIP := headers["X-Forwarded-For"]; clientIP != "" {
+// extractClientID extracts client identifier from the request.
+// With trusted_proxies configured, only forwarding headers added by those proxies are believed.
+// Callers must hold p.mu.
+func (p *RateLimitPlugin) extractClientID(req *pluginv1.HTTPRequest) string {
+	if len(p.trustedProxies) > 0 {
+		if clientIP := resolveClientIP(req.RemoteAddr, req.Headers["X-Forwarded-For"], p.trustedProxies); clientIP != "" {
+			return clientIP
+		}
+
+		return "unknown"
+	}
+
+	if clientIP := req.Headers["X-Forwarded-For"]; clientIP != "" {
 		return clientIP
 	}
 
-	if clientIP := headers["X-Real-IP"]; clientIP != "" {
+	if clientIP := req.Headers["X-Real-IP"]; clientIP != "" {
 		return clientIP
 	}
 
+	if clientIP, ok := parseIP(req.RemoteAddr); ok {
+		return clientIP.String()
+	}
+
 	return "unknown"
 }
 
-// isRateLimited checks if client has exceeded rate limit.
-func (p *RateLimitPlugin) isRateLimited(clientID string) bool {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-
-	if time.Since(p.lastReset) >= p.window {
-		p.requests = make(map[string]int)
-		p.lastReset = time.Now()
+// defaultRule returns the limit applied when no configured rule matches.
+// Callers must hold p.mu.
+func (p *RateLimitPlugin) defaultRule() rateLimitRule {
+	return rateLimitRule{
+		name:        defaultRuleName,
+		maxRequests: p.maxRequests,
+		window:      p.window,
 	}
-
-	count := p.requests[clientID]
-	return count >= p.maxRequests
 }
 
-// incrementRequest increments request count for client and returns remaining requests.
-func (p *RateLimitPlugin) incrementRequest(clientID string) int {
-	p.mu.Lock()
-	defer p.mu.Unlock()
+// newStoreFromConfig returns a Redis-backed store when redis_url is configured, otherwise a local one.
+func newStoreFromConfig(cfg map[string]string) (counterStore, error) {
+	url := cfg["redis_url"]
+	if url == "" {
+		return newMemoryStore(), nil
+	}
+
+	var timeout time.Duration
+	if timeoutStr, exists := cfg["redis_timeout"]; exists {
+		t, err := time.ParseDuration(timeoutStr)
+		if err != nil || t <= 0 {
+			return nil, fmt.Errorf("invalid redis_timeout %q: must be a positive duration", timeoutStr)
+		}
+		timeout = t
+	}
 
-	if time.Since(p.lastReset) >= p.window {
-		p.requests = make(map[string]int)
-		p.lastReset = time.Now()
+	store, err := newRedisStore(url, cfg["redis_key_prefix"], timeout)
+	if err != nil {
+		return nil, err
 	}
 
-	p.requests[clientID]++
-	return p.maxRequests - p.requests[clientID]
+	log.Printf("Rate limit using Redis counters with key prefix %q", store.keyPrefix)
+
+	return store, nil
 }
 
-// getResetTime returns the timestamp when rate limits will reset.
-func (p *RateLimitPlugin) getResetTime() int64 {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+// retryAfterSeconds returns the whole number of seconds until resetAt, never less than one.
+func retryAfterSeconds(resetAt time.Time) int64 {
+	secs := int64(math.Ceil(time.Until(resetAt).Seconds()))
+	if secs < 1 {
+		return 1
+	}
 
-	return p.lastReset.Add(p.window).Unix()
+	return secs
 }
 
 func main() {
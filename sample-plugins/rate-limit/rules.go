@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRuleName = "default"
+	toolNameHeader  = "x-tool-name"
+)
+
+// ruleConfig is the JSON form of a rule supplied via the "rules" custom config key.
+type ruleConfig struct {
+	Name        string `json:"name"`
+	PathPrefix  string `json:"pathPrefix"`
+	Tool        string `json:"tool"`
+	MaxRequests int    `json:"maxRequests"`
+	Window      string `json:"window"`
+}
+
+// rateLimitRule is a limit applied to requests matching a path prefix and/or tool name.
+type rateLimitRule struct {
+	name        string
+	pathPrefix  string
+	tool        string
+	maxRequests int
+	window      time.Duration
+}
+
+// parseRules decodes and validates a JSON array of rules.
+func parseRules(raw string) ([]rateLimitRule, error) {
+	var cfgs []ruleConfig
+	if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+
+	rules := make([]rateLimitRule, 0, len(cfgs))
+	names := make(map[string]struct{}, len(cfgs))
+
+	for i, c := range cfgs {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i)
+		}
+
+		if name == defaultRuleName {
+			return nil, fmt.Errorf("rule %d: name %q is reserved", i, defaultRuleName)
+		}
+
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i, name)
+		}
+		names[name] = struct{}{}
+
+		if c.PathPrefix == "" && c.Tool == "" {
+			return nil, fmt.Errorf("rule %q: at least one of pathPrefix or tool is required", name)
+		}
+
+		if c.MaxRequests <= 0 {
+			return nil, fmt.Errorf("rule %q: maxRequests must be positive", name)
+		}
+
+		window, err := time.ParseDuration(c.Window)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("rule %q: invalid window %q", name, c.Window)
+		}
+
+		rules = append(rules, rateLimitRule{
+			name:        name,
+			pathPrefix:  c.PathPrefix,
+			tool:        c.Tool,
+			maxRequests: c.MaxRequests,
+			window:      window,
+		})
+	}
+
+	return rules, nil
+}
+
+// matches reports whether every matcher set on the rule applies to the request.
+func (r rateLimitRule) matches(path string, tool string) bool {
+	if r.pathPrefix != "" && !strings.HasPrefix(path, r.pathPrefix) {
+		return false
+	}
+
+	if r.tool != "" && r.tool != tool {
+		return false
+	}
+
+	return true
+}
+
+// specificity ranks matching rules: a tool match outranks any path match,
+// and longer path prefixes outrank shorter ones.
+func (r rateLimitRule) specificity() int {
+	score := len(r.pathPrefix)
+	if r.tool != "" {
+		score += 1 << 16
+	}
+
+	return score
+}
+
+// selectRule returns the most specific rule matching the request, or fallback when none match.
+// Ties are broken by declaration order.
+func selectRule(rules []rateLimitRule, fallback rateLimitRule, path string, tool string) rateLimitRule {
+	selected := fallback
+	best := -1
+
+	for _, r := range rules {
+		if !r.matches(path, tool) {
+			continue
+		}
+
+		if s := r.specificity(); s > best {
+			selected = r
+			best = s
+		}
+	}
+
+	return selected
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
+}
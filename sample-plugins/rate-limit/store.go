@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// counterStore tracks fixed-window request counters.
+type counterStore interface {
+	// Increment adds one to the counter for key and returns the new count together with
+	// the time the counter's current window resets. A new window starts when none is active.
+	Increment(ctx context.Context, key string, window time.Duration) (count int64, resetAt time.Time, err error)
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// windowCounter is a single in-memory counter and the end of its window.
+type windowCounter struct {
+	count   int64
+	resetAt time.Time
+}
+
+// memoryStore is a process-local counterStore.
+// Limits are only enforced per plugin instance when using this store.
+type memoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*windowCounter
+	nextSweep time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		counters: make(map[string]*windowCounter),
+	}
+}
+
+func (s *memoryStore) Increment(_ context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now, window)
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.resetAt) {
+		c = &windowCounter{resetAt: now.Add(window)}
+		s.counters[key] = c
+	}
+
+	c.count++
+
+	return c.count, c.resetAt, nil
+}
+
+func (s *memoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters = make(map[string]*windowCounter)
+
+	return nil
+}
+
+// sweep removes expired counters at most once per window so idle clients don't accumulate.
+// Callers must hold s.mu.
+func (s *memoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+
+	for key, c := range s.counters {
+		if !now.Before(c.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+
+	s.nextSweep = now.Add(window)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRedisKeyPrefix = "mcpd:ratelimit:"
+	defaultRedisTimeout   = 100 * time.Millisecond
+)
+
+// incrementScript atomically increments a counter, starts its window on first use,
+// and returns the new count with the remaining window in milliseconds.
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+// redisStore is a counterStore shared by every plugin instance using the same Redis.
+// When Redis cannot be reached, counting falls back to a process-local store
+// so requests keep being limited (per instance) rather than failing.
+type redisStore struct {
+	client    *redis.Client
+	keyPrefix string
+	timeout   time.Duration
+	fallback  *memoryStore
+	degraded  atomic.Bool
+}
+
+// newRedisStore creates a store for the given redis:// or rediss:// URL.
+func newRedisStore(url string, keyPrefix string, timeout time.Duration) (*redisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis_url: %w", err)
+	}
+
+	if keyPrefix == "" {
+		keyPrefix = defaultRedisKeyPrefix
+	}
+
+	if timeout <= 0 {
+		timeout = defaultRedisTimeout
+	}
+
+	return &redisStore{
+		client:    redis.NewClient(opts),
+		keyPrefix: keyPrefix,
+		timeout:   timeout,
+		fallback:  newMemoryStore(),
+	}, nil
+}
+
+func (s *redisStore) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	res, err := incrementScript.Run(ctx, s.client, []string{s.keyPrefix + key}, window.Milliseconds()).Int64Slice()
+	if err == nil && len(res) != 2 {
+		err = fmt.Errorf("unexpected script result: %v", res)
+	}
+
+	if err != nil {
+		if !s.degraded.Swap(true) {
+			log.Printf("Rate limit Redis unavailable, falling back to local counters: %v", err)
+		}
+		return s.fallback.Increment(ctx, key, window)
+	}
+
+	if s.degraded.Swap(false) {
+		log.Println("Rate limit Redis reachable again, resuming shared counters")
+	}
+
+	return res[0], time.Now().Add(time.Duration(res[1]) * time.Millisecond), nil
+}
+
+func (s *redisStore) Close() error {
+	_ = s.fallback.Close()
+	return s.client.Close()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const defaultMaxErrors = 20
+
+// errorBody is the machine-readable 400 response body.
+type errorBody struct {
+	Error   string      `json:"error"`
+	Message string      `json:"message"`
+	Errors  []violation `json:"errors,omitempty"`
+}
+
+// SchemaValidationPlugin validates request bodies against JSON Schemas keyed by method and path.
+type SchemaValidationPlugin struct {
+	pluginv1.BasePlugin
+
+	mu          sync.RWMutex
+	routes      []routeSchema
+	maxErrors   int
+	initialized bool
+}
+
+func newSchemaValidationPlugin() *SchemaValidationPlugin {
+	return &SchemaValidationPlugin{
+		maxErrors: defaultMaxErrors,
+	}
+}
+
+func (p *SchemaValidationPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "schema-validation",
+		Version:     "1.0.0",
+		Description: "Validates request bodies against JSON Schemas",
+	}, nil
+}
+
+func (p *SchemaValidationPlugin) GetCapabilities(
+	ctx context.Context,
+	_ *emptypb.Empty,
+) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest},
+	}, nil
+}
+
+func (p *SchemaValidationPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	var routes []routeSchema
+	if schemasStr, exists := custom["schemas"]; exists {
+		loaded, err := loadSchemas(schemasStr, custom["schema_dir"])
+		if err != nil {
+			return nil, fmt.Errorf("schema validation plugin configuration failed: %w", err)
+		}
+		routes = loaded
+	}
+
+	maxErrors := defaultMaxErrors
+	if maxErrStr, exists := custom["max_errors"]; exists {
+		n, err := strconv.Atoi(maxErrStr)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("schema validation plugin configuration failed: invalid max_errors %q", maxErrStr)
+		}
+		maxErrors = n
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.routes = routes
+	p.maxErrors = maxErrors
+	p.initialized = true
+
+	log.Printf("Schema validation plugin initialized with %d schemas", len(routes))
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SchemaValidationPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Schema validation plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.routes = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SchemaValidationPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("schema validation plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SchemaValidationPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("schema validation plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SchemaValidationPlugin) HandleRequest(
+	ctx context.Context,
+	req *pluginv1.HTTPRequest,
+) (*pluginv1.HTTPResponse, error) {
+	log.Printf("Schema validation handling request: %s %s", req.Method, req.Path)
+
+	p.mu.RLock()
+	initialized, routes, maxErrors := p.initialized, p.routes, p.maxErrors
+	p.mu.RUnlock()
+
+	if !initialized {
+		return nil, fmt.Errorf("schema validation plugin not initialized")
+	}
+
+	method := strings.ToUpper(req.Method)
+	reqPath := path.Clean("/" + req.Path)
+
+	route, ok := findSchema(routes, method, reqPath)
+	if !ok {
+		return &pluginv1.HTTPResponse{Continue: true}, nil
+	}
+
+	if len(req.Body) == 0 {
+		return badRequest("request body is required", nil)
+	}
+
+	violations, err := validateBody(route.schema, req.Body, maxErrors)
+	if err != nil {
+		log.Printf("Schema validation could not parse body for %s %s: %v", method, reqPath, err)
+		return badRequest("request body is not valid JSON", nil)
+	}
+
+	if len(violations) > 0 {
+		log.Printf("Schema validation rejected %s %s: %d violations", method, reqPath, len(violations))
+		return badRequest("request body failed schema validation", violations)
+	}
+
+	return &pluginv1.HTTPResponse{Continue: true}, nil
+}
+
+// badRequest builds a 400 response listing the schema violations.
+func badRequest(message string, violations []violation) (*pluginv1.HTTPResponse, error) {
+	body, err := json.Marshal(errorBody{
+		Error:   "Bad Request",
+		Message: message,
+		Errors:  violations,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode validation response: %w", err)
+	}
+
+	return &pluginv1.HTTPResponse{
+		Continue:   false,
+		StatusCode: 400,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+		Body: body,
+	}, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newSchemaValidationPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
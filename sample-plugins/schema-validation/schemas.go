@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaConfig is the JSON form of an entry in the "schemas" custom config value.
+// Exactly one of Schema (inline) or File must be set.
+type schemaConfig struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Schema json.RawMessage `json:"schema"`
+	File   string          `json:"file"`
+}
+
+// routeSchema is a compiled schema bound to a method and path pattern.
+type routeSchema struct {
+	method string
+	path   string
+	schema *jsonschema.Schema
+}
+
+// violation is a single schema validation failure reported to the client.
+type violation struct {
+	InstanceLocation string `json:"instanceLocation"`
+	KeywordLocation  string `json:"keywordLocation"`
+	Message          string `json:"message"`
+}
+
+// loadSchemas compiles the configured schemas. Relative file paths are resolved against dir.
+func loadSchemas(raw string, dir string) ([]routeSchema, error) {
+	var cfgs []schemaConfig
+	if err := json.Unmarshal([]byte(raw), &cfgs); err != nil {
+		return nil, fmt.Errorf("invalid schemas: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+
+	routes := make([]routeSchema, 0, len(cfgs))
+	for i, c := range cfgs {
+		if c.Path == "" {
+			return nil, fmt.Errorf("schemas entry %d: path is required", i)
+		}
+
+		if _, err := path.Match(c.Path, "/"); err != nil {
+			return nil, fmt.Errorf("schemas entry %d: invalid path pattern %q", i, c.Path)
+		}
+
+		hasInline := len(c.Schema) > 0 && string(c.Schema) != "null"
+		if hasInline == (c.File != "") {
+			return nil, fmt.Errorf("schemas entry %d: exactly one of schema or file is required", i)
+		}
+
+		var loc string
+		if hasInline {
+			doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(c.Schema))
+			if err != nil {
+				return nil, fmt.Errorf("schemas entry %d: invalid inline schema: %w", i, err)
+			}
+
+			loc = fmt.Sprintf("mem://schemas/%d.json", i)
+			if err := compiler.AddResource(loc, doc); err != nil {
+				return nil, fmt.Errorf("schemas entry %d: %w", i, err)
+			}
+		} else {
+			loc = c.File
+			if !filepath.IsAbs(loc) && dir != "" {
+				loc = filepath.Join(dir, loc)
+			}
+		}
+
+		schema, err := compiler.Compile(loc)
+		if err != nil {
+			return nil, fmt.Errorf("schemas entry %d: failed to compile schema: %w", i, err)
+		}
+
+		routes = append(routes, routeSchema{
+			method: strings.ToUpper(c.Method),
+			path:   c.Path,
+			schema: schema,
+		})
+	}
+
+	return routes, nil
+}
+
+// findSchema returns the first schema whose method and path pattern match the request.
+func findSchema(routes []routeSchema, method string, reqPath string) (routeSchema, bool) {
+	for _, r := range routes {
+		if r.method != "" && r.method != "*" && r.method != method {
+			continue
+		}
+
+		if matched, _ := path.Match(r.path, reqPath); matched {
+			return r, true
+		}
+	}
+
+	return routeSchema{}, false
+}
+
+// validateBody checks the JSON body against the schema and returns at most maxErrors violations.
+func validateBody(schema *jsonschema.Schema, body []byte, maxErrors int) ([]violation, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	err = schema.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return nil, err
+	}
+
+	var violations []violation
+	for _, unit := range ve.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+
+		violations = append(violations, violation{
+			InstanceLocation: unit.InstanceLocation,
+			KeywordLocation:  unit.KeywordLocation,
+			Message:          unit.Error.String(),
+		})
+
+		if len(violations) >= maxErrors {
+			break
+		}
+	}
+
+	if len(violations) == 0 {
+		var location string
+		if len(ve.InstanceLocation) > 0 {
+			location = "/" + strings.Join(ve.InstanceLocation, "/")
+		}
+
+		violations = append(violations, violation{
+			InstanceLocation: location,
+			Message:          ve.Error(),
+		})
+	}
+
+	return violations, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultHeaders suit JSON APIs such as MCP endpoints, which never need to be framed or run scripts.
+var defaultHeaders = map[string]string{
+	"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+	"Content-Security-Policy":   "default-src 'none'; frame-ancestors 'none'",
+	"X-Content-Type-Options":    "nosniff",
+	"X-Frame-Options":           "DENY",
+	"Referrer-Policy":           "no-referrer",
+}
+
+// newHeaders returns the default headers with the "headers" custom config value applied.
+func newHeaders(headersJSON string) (map[string]string, error) {
+	headers := make(map[string]string, len(defaultHeaders))
+	for k, v := range defaultHeaders {
+		headers[k] = v
+	}
+
+	if headersJSON == "" {
+		return headers, nil
+	}
+
+	var changes map[string]string
+	if err := json.Unmarshal([]byte(headersJSON), &changes); err != nil {
+		return nil, fmt.Errorf("invalid headers: expected a JSON object of header to value: %w", err)
+	}
+	if err := merge(headers, changes); err != nil {
+		return nil, err
+	}
+
+	return headers, nil
+}
+
+// merge applies headers onto dst, deleting any header whose value is empty.
+func merge(dst map[string]string, headers map[string]string) error {
+	for k, v := range headers {
+		if k == "" || strings.ContainsAny(k, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q", k)
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return fmt.Errorf("invalid value for header %s: must not contain line breaks", k)
+		}
+
+		deleteHeader(dst, k)
+		if v != "" {
+			dst[k] = v
+		}
+	}
+
+	return nil
+}
+
+// deleteHeader removes every case variant of a header.
+func deleteHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
+
+// hasHeader reports whether any case variant of a header is present.
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
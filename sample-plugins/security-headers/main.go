@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"strconv"
+	"sync"
+
+	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// SecurityHeadersPlugin adds security headers such as HSTS and CSP to every response.
+type SecurityHeadersPlugin struct {
+	pluginv1.BasePlugin
+
+	mu               sync.RWMutex
+	headers          map[string]string
+	overrideExisting bool
+	initialized      bool
+}
+
+func newSecurityHeadersPlugin() *SecurityHeadersPlugin {
+	return &SecurityHeadersPlugin{}
+}
+
+func (p *SecurityHeadersPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
+	return &pluginv1.Metadata{
+		Name:        "security-headers",
+		Version:     "1.0.0",
+		Description: "Adds HSTS, CSP, X-Frame-Options and related security headers to responses",
+	}, nil
+}
+
+func (p *SecurityHeadersPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
+	return &pluginv1.Capabilities{
+		Flows: []pluginv1.Flow{pluginv1.FlowResponse},
+	}, nil
+}
+
+func (p *SecurityHeadersPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	custom := cfg.GetCustomConfig()
+
+	// Responses don't carry the request path, and mcpd doesn't copy request headers onto responses, so the
+	// response flow has no way to tell which path a response belongs to.
+	if _, exists := custom["overrides"]; exists {
+		return nil, errors.New(
+			"security headers plugin configuration failed: per-path overrides are not supported, " +
+				"since the host doesn't pass the request path to the response flow",
+		)
+	}
+
+	headers, err := newHeaders(custom["headers"])
+	if err != nil {
+		return nil, fmt.Errorf("security headers plugin configuration failed: %w", err)
+	}
+
+	overrideExisting := false
+	if v, exists := custom["override_existing"]; exists {
+		overrideExisting, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("security headers plugin configuration failed: invalid override_existing %q", v)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.headers = headers
+	p.overrideExisting = overrideExisting
+	p.initialized = true
+
+	log.Printf(
+		"Security headers plugin initialized (%d headers, override existing: %t)",
+		len(headers),
+		overrideExisting,
+	)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SecurityHeadersPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	log.Println("Security headers plugin cleaning up...")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.headers = nil
+	p.initialized = false
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SecurityHeadersPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("security headers plugin not initialized")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SecurityHeadersPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.initialized {
+		return nil, fmt.Errorf("security headers plugin not ready")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (p *SecurityHeadersPlugin) HandleResponse(
+	ctx context.Context,
+	resp *pluginv1.HTTPResponse,
+) (*pluginv1.HTTPResponse, error) {
+	p.mu.RLock()
+	add, overrideExisting := p.headers, p.overrideExisting
+	p.mu.RUnlock()
+
+	if add == nil {
+		return p.BasePlugin.HandleResponse(ctx, resp)
+	}
+
+	headers := maps.Clone(resp.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+
+	for name, value := range add {
+		if hasHeader(headers, name) {
+			if !overrideExisting {
+				continue
+			}
+			deleteHeader(headers, name)
+		}
+		headers[name] = value
+	}
+
+	return &pluginv1.HTTPResponse{
+		Continue:   true,
+		StatusCode: resp.StatusCode,
+		Headers:    headers,
+		Body:       resp.Body,
+	}, nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("")
+
+	if err := pluginv1.Serve(newSecurityHeadersPlugin()); err != nil {
+		log.Fatal(err)
+	}
+}
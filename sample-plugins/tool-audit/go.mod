@@ -5,6 +5,7 @@ go 1.25.1
 require (
 	github.com/mozilla-ai/mcpd-plugins-sdk-go v0.0.1
 	google.golang.org/protobuf v1.36.10
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
 require (
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	pluginv1 "github.com/mozilla-ai/mcpd-plugins-sdk-go/pkg/plugins/v1/plugins"
@@ -16,11 +17,16 @@ import (
 type ToolAuditPlugin struct {
 	pluginv1.BasePlugin
 
+	mu          sync.RWMutex
+	sink        *asyncSink
+	pending     *pendingCalls
 	initialized bool
 }
 
 func newToolAuditPlugin() *ToolAuditPlugin {
-	return &ToolAuditPlugin{}
+	return &ToolAuditPlugin{
+		pending: newPendingCalls(),
+	}
 }
 
 func (p *ToolAuditPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Metadata, error) {
@@ -33,23 +39,56 @@ func (p *ToolAuditPlugin) GetMetadata(ctx context.Context, _ *emptypb.Empty) (*p
 
 func (p *ToolAuditPlugin) GetCapabilities(ctx context.Context, _ *emptypb.Empty) (*pluginv1.Capabilities, error) {
 	return &pluginv1.Capabilities{
-		Flows: []pluginv1.Flow{pluginv1.FlowRequest, pluginv1.FlowRequest},
+		Flows: []pluginv1.Flow{pluginv1.FlowRequest, pluginv1.FlowResponse},
 	}, nil
 }
 
 func (p *ToolAuditPlugin) Configure(ctx context.Context, cfg *pluginv1.PluginConfig) (*emptypb.Empty, error) {
+	sink, err := newSinkFromConfig(cfg.GetCustomConfig())
+	if err != nil {
+		return nil, fmt.Errorf("tool audit plugin configuration failed: %w", err)
+	}
+
+	p.mu.Lock()
+	previous := p.sink
+	p.sink = sink
 	p.initialized = true
+	p.mu.Unlock()
+
+	// Flush and release the previous sink when reconfigured. This happens outside the lock, since draining
+	// the buffer can take a while and requests must not wait for it.
+	if previous != nil {
+		if err := previous.Close(ctx); err != nil {
+			log.Printf("[ERROR] Failed to close previous audit sink: %v", err)
+		}
+	}
+
 	log.Println("Tool audit plugin initialized successfully")
 	return &emptypb.Empty{}, nil
 }
 
 func (p *ToolAuditPlugin) Stop(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
 	log.Println("Tool audit plugin cleaning up...")
+
+	p.mu.Lock()
+	sink := p.sink
+	p.sink = nil
 	p.initialized = false
+	p.mu.Unlock()
+
+	if sink != nil {
+		if err := sink.Close(ctx); err != nil {
+			log.Printf("[ERROR] Failed to flush audit sink: %v", err)
+		}
+	}
+
 	return &emptypb.Empty{}, nil
 }
 
 func (p *ToolAuditPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if !p.initialized {
 		return nil, fmt.Errorf("tool audit plugin not initialized")
 	}
@@ -57,6 +96,9 @@ func (p *ToolAuditPlugin) CheckHealth(ctx context.Context, _ *emptypb.Empty) (*e
 }
 
 func (p *ToolAuditPlugin) CheckReady(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
 	if !p.initialized {
 		return nil, fmt.Errorf("tool audit plugin not ready")
 	}
@@ -69,6 +111,13 @@ func (p *ToolAuditPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPR
 	auditInfo := p.extractAuditInfo(req)
 	p.logToolUsage(auditInfo)
 
+	now := time.Now()
+	for _, id := range toolCallIDs(req.Body) {
+		if !p.pending.Add(id, now) {
+			log.Printf("[WARN] Tool audit is tracking too many calls; the result of call %s won't be recorded", id)
+		}
+	}
+
 	headers := make(map[string]string)
 	for k, v := range req.Headers {
 		headers[k] = v
@@ -83,6 +132,24 @@ func (p *ToolAuditPlugin) HandleRequest(ctx context.Context, req *pluginv1.HTTPR
 	}, nil
 }
 
+func (p *ToolAuditPlugin) HandleResponse(
+	ctx context.Context,
+	resp *pluginv1.HTTPResponse,
+) (*pluginv1.HTTPResponse, error) {
+	// Compressed bodies can't be inspected; the request entry is still there. Only responses to a tracked
+	// tools/call are recorded, which also skips responses without an ID.
+	if enc := headerValue(resp.Headers, "Content-Encoding"); enc == "" || strings.EqualFold(enc, "identity") {
+		now := time.Now()
+		for _, result := range parseToolResults(headerValue(resp.Headers, "Content-Type"), resp.Body) {
+			if len(result.ID) > 0 && p.pending.Take(result.ID, now) {
+				p.logToolResult(resp, result)
+			}
+		}
+	}
+
+	return p.BasePlugin.HandleResponse(ctx, resp)
+}
+
 // auditInfo represents extracted audit information.
 type auditInfo struct {
 	Timestamp   time.Time         `json:"timestamp"`
@@ -94,6 +161,14 @@ type auditInfo struct {
 	ContentType string            `json:"content_type,omitempty"`
 	BodyPreview string            `json:"body_preview,omitempty"`
 	Headers     map[string]string `json:"headers,omitempty"`
+
+	// Correlation identifiers, when the host or client supplies them.
+	RequestID     string `json:"request_id,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	TraceID       string `json:"trace_id,omitempty"`
+
+	// JSONRPCID is the ID of the JSON-RPC call, used to match the tool_result entry.
+	JSONRPCID json.RawMessage `json:"jsonrpc_id,omitempty"`
 }
 
 // extractAuditInfo extracts relevant audit information from the request.
@@ -121,10 +196,21 @@ func (p *ToolAuditPlugin) extractAuditInfo(req *pluginv1.HTTPRequest) auditInfo
 		info.ContentType = ct
 	}
 
+	info.RequestID = headerValue(req.Headers, "X-Request-Id")
+	info.CorrelationID = headerValue(req.Headers, "X-Correlation-Id")
+	info.TraceID = traceIDFromParent(headerValue(req.Headers, "traceparent"))
+
 	if len(req.Body) > 0 && strings.Contains(info.ContentType, "application/json") {
 		info.BodyPreview = p.extractToolFromBody(req.Body)
 	}
 
+	var call struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if len(req.Body) > 0 && json.Unmarshal(req.Body, &call) == nil && len(call.ID) > 0 {
+		info.JSONRPCID = call.ID
+	}
+
 	return info
 }
 
@@ -163,7 +249,7 @@ func (p *ToolAuditPlugin) extractToolFromBody(body []byte) string {
 	return bodyStr
 }
 
-// logToolUsage logs the tool usage audit information.
+// logToolUsage writes the tool usage audit information to the configured sink.
 func (p *ToolAuditPlugin) logToolUsage(info auditInfo) {
 	logEntry := map[string]interface{}{
 		"audit_type": "tool_usage",
@@ -194,12 +280,111 @@ func (p *ToolAuditPlugin) logToolUsage(info auditInfo) {
 		logEntry["body_preview"] = info.BodyPreview
 	}
 
-	if jsonLog, err := json.Marshal(logEntry); err == nil {
-		log.Printf("[INFO] AUDIT: %s", string(jsonLog))
-	} else {
+	if info.RequestID != "" {
+		logEntry["request_id"] = info.RequestID
+	}
+
+	if info.CorrelationID != "" {
+		logEntry["correlation_id"] = info.CorrelationID
+	}
+
+	if info.TraceID != "" {
+		logEntry["trace_id"] = info.TraceID
+	}
+
+	if len(info.JSONRPCID) > 0 {
+		logEntry["jsonrpc_id"] = info.JSONRPCID
+	}
+
+	jsonLog, err := json.Marshal(logEntry)
+	if err != nil {
 		log.Printf("[INFO] AUDIT: %s %s - MCP Server: %s, Tool: %s",
 			info.Method, info.Path, info.MCPServer, info.ToolName)
+		return
+	}
+
+	p.writeEntry(jsonLog)
+}
+
+// logToolResult writes the outcome of a tool call, taken from the MCP response, to the configured sink.
+// Entries are joined to their tool_usage entry by the JSON-RPC ID and any correlation headers.
+func (p *ToolAuditPlugin) logToolResult(resp *pluginv1.HTTPResponse, result toolResult) {
+	logEntry := map[string]interface{}{
+		"audit_type":  "tool_result",
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"status_code": resp.StatusCode,
+		"outcome":     result.Outcome,
 	}
+
+	logEntry["jsonrpc_id"] = result.ID
+
+	if result.Outcome == outcomeRPCError {
+		logEntry["error_code"] = result.ErrorCode
+		logEntry["error_message"] = result.ErrorMessage
+	}
+
+	if id := headerValue(resp.Headers, "X-Request-Id"); id != "" {
+		logEntry["request_id"] = id
+	}
+
+	if id := headerValue(resp.Headers, "X-Correlation-Id"); id != "" {
+		logEntry["correlation_id"] = id
+	}
+
+	jsonLog, err := json.Marshal(logEntry)
+	if err != nil {
+		log.Printf("[INFO] AUDIT: tool result %s (status %d)", result.Outcome, resp.StatusCode)
+		return
+	}
+
+	p.writeEntry(jsonLog)
+}
+
+// writeEntry delivers an encoded audit entry to the sink.
+func (p *ToolAuditPlugin) writeEntry(jsonLog []byte) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	// Fall back to the log when no sink has been configured yet.
+	if p.sink == nil {
+		log.Printf("[INFO] AUDIT: %s", string(jsonLog))
+		return
+	}
+
+	if err := p.sink.Write(jsonLog); err != nil {
+		log.Printf("[WARN] Audit entry not delivered: %v", err)
+	}
+}
+
+// traceIDFromParent extracts the trace ID from a W3C traceparent header ("00-<trace-id>-<parent-id>-<flags>").
+func traceIDFromParent(traceparent string) string {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || parts[1] == strings.Repeat("0", 32) {
+		return ""
+	}
+
+	for _, c := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return ""
+		}
+	}
+
+	return parts[1]
+}
+
+// headerValue returns the value of a header using a case-insensitive name lookup.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+
+	return ""
 }
 
 func main() {
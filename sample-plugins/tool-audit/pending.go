@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	// pendingCallTTL is how long a tools/call request waits for its response before it's forgotten.
+	pendingCallTTL = 5 * time.Minute
+
+	// maxPendingCalls bounds the calls awaiting a response, so clients that never read their responses can't
+	// grow the set without limit.
+	maxPendingCalls = 10000
+)
+
+// pendingCalls tracks the JSON-RPC IDs of tools/call requests awaiting a response. Only responses to these
+// get a tool_result entry, since a response carries no method of its own and would otherwise be
+// indistinguishable from the result of initialize, tools/list and the like.
+//
+// IDs are only unique per client, so two clients using the same ID at once share an entry.
+type pendingCalls struct {
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	nextSweep time.Time
+}
+
+func newPendingCalls() *pendingCalls {
+	return &pendingCalls{
+		expires: make(map[string]time.Time),
+	}
+}
+
+// Add records id as awaiting a response. Calls whose responses never arrived are swept at most once per
+// pendingCallTTL; if the set is still full the call isn't tracked, and its outcome goes unrecorded.
+func (p *pendingCalls) Add(id json.RawMessage, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !now.Before(p.nextSweep) {
+		for k, exp := range p.expires {
+			if now.After(exp) {
+				delete(p.expires, k)
+			}
+		}
+		p.nextSweep = now.Add(pendingCallTTL)
+	}
+
+	key := callKey(id)
+	if _, exists := p.expires[key]; !exists && len(p.expires) >= maxPendingCalls {
+		return false
+	}
+
+	p.expires[key] = now.Add(pendingCallTTL)
+
+	return true
+}
+
+// Take reports whether id was pending and clears it.
+func (p *pendingCalls) Take(id json.RawMessage, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := callKey(id)
+	exp, ok := p.expires[key]
+	delete(p.expires, key)
+
+	return ok && !now.After(exp)
+}
+
+// callKey normalizes the whitespace in an ID so the request and response forms match.
+func callKey(id json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, id); err != nil {
+		return string(id)
+	}
+
+	return buf.String()
+}
+
+// toolCallIDs returns the IDs of the tools/call requests in a JSON-RPC body, single or batch.
+// Notifications have no ID and get no response, so they're skipped.
+func toolCallIDs(body []byte) []json.RawMessage {
+	type rpcRequest struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+	}
+
+	body = bytes.TrimSpace(body)
+
+	var batch []rpcRequest
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return nil
+		}
+	} else {
+		var single rpcRequest
+		if err := json.Unmarshal(body, &single); err != nil {
+			return nil
+		}
+		batch = []rpcRequest{single}
+	}
+
+	var ids []json.RawMessage
+	for _, r := range batch {
+		if r.Method == "tools/call" && len(r.ID) > 0 && string(r.ID) != "null" {
+			ids = append(ids, r.ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// Tool call outcomes recorded in tool_result audit entries.
+const (
+	outcomeSuccess   = "success"
+	outcomeToolError = "tool_error"
+	outcomeRPCError  = "rpc_error"
+)
+
+// rpcResponse is the part of a JSON-RPC 2.0 response the audit needs.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  *struct {
+		IsError bool `json:"isError"`
+	} `json:"result"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// toolResult is the outcome of one JSON-RPC response.
+type toolResult struct {
+	ID           json.RawMessage
+	Outcome      string
+	ErrorCode    int
+	ErrorMessage string
+}
+
+// parseToolResults extracts JSON-RPC outcomes from an MCP response body.
+// Plain JSON (single or batch) and server-sent event streams are supported; anything else yields nothing.
+func parseToolResults(contentType string, body []byte) []toolResult {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+
+	switch strings.TrimSpace(mediaType) {
+	case "application/json":
+		return decodeRPCResponses(body)
+	case "text/event-stream":
+		var results []toolResult
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				results = append(results, decodeRPCResponses([]byte(data))...)
+			}
+		}
+		return results
+	default:
+		return nil
+	}
+}
+
+func decodeRPCResponses(data []byte) []toolResult {
+	data = bytes.TrimSpace(data)
+
+	var batch []rpcResponse
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil
+		}
+	} else {
+		var single rpcResponse
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil
+		}
+		batch = []rpcResponse{single}
+	}
+
+	var results []toolResult
+	for _, r := range batch {
+		// Skip requests and notifications the server sends on the same stream.
+		if r.JSONRPC != "2.0" || (r.Result == nil && r.Error == nil) {
+			continue
+		}
+
+		res := toolResult{ID: r.ID, Outcome: outcomeSuccess}
+		switch {
+		case r.Error != nil:
+			res.Outcome = outcomeRPCError
+			res.ErrorCode = r.Error.Code
+			res.ErrorMessage = r.Error.Message
+		case r.Result.IsError:
+			res.Outcome = outcomeToolError
+		}
+
+		results = append(results, res)
+	}
+
+	return results
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	sinkStdout  = "stdout"
+	sinkFile    = "file"
+	sinkSyslog  = "syslog"
+	sinkWebhook = "webhook"
+)
+
+const (
+	defaultBufferSize     = 1024
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 5
+	defaultWebhookTimeout = 5 * time.Second
+	defaultSyslogTag      = "tool-audit"
+)
+
+// auditSink is a destination for serialized audit entries.
+type auditSink interface {
+	// Write delivers a single JSON-encoded audit entry.
+	Write(entry []byte) error
+
+	// Close flushes any pending entries and releases resources.
+	Close() error
+}
+
+// newSinkFromConfig builds the audit sink selected by the plugin's custom configuration.
+// The returned sink is always wrapped so that writes never block request handling.
+func newSinkFromConfig(cfg map[string]string) (*asyncSink, error) {
+	kind := strings.ToLower(strings.TrimSpace(cfg["sink"]))
+	if kind == "" {
+		kind = sinkStdout
+	}
+
+	bufferSize, err := positiveIntOrDefault(cfg, "buffer_size", defaultBufferSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var sink auditSink
+
+	switch kind {
+	case sinkStdout:
+		sink = &stdoutSink{}
+	case sinkFile:
+		sink, err = newFileSink(cfg)
+	case sinkSyslog:
+		sink, err = newSyslogSink(cfg)
+	case sinkWebhook:
+		sink, err = newWebhookSink(cfg)
+	default:
+		return nil, fmt.Errorf("unknown sink %q: must be one of %s, %s, %s, %s",
+			kind, sinkStdout, sinkFile, sinkSyslog, sinkWebhook)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s sink: %w", kind, err)
+	}
+
+	log.Printf("Tool audit sink configured: %s (buffer size %d)", kind, bufferSize)
+
+	return newAsyncSink(sink, bufferSize), nil
+}
+
+// stdoutSink writes audit entries through the standard logger.
+type stdoutSink struct{}
+
+func (s *stdoutSink) Write(entry []byte) error {
+	log.Printf("[INFO] AUDIT: %s", entry)
+	return nil
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
+
+// fileSink appends audit entries as JSON lines to a size-rotated file.
+type fileSink struct {
+	logger *lumberjack.Logger
+}
+
+func newFileSink(cfg map[string]string) (*fileSink, error) {
+	path := cfg["file_path"]
+	if path == "" {
+		return nil, fmt.Errorf("file_path is required")
+	}
+
+	maxSize, err := positiveIntOrDefault(cfg, "file_max_size_mb", defaultFileMaxSizeMB)
+	if err != nil {
+		return nil, err
+	}
+
+	maxBackups, err := positiveIntOrDefault(cfg, "file_max_backups", defaultFileMaxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSink{
+		logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			Compress:   cfg["file_compress"] == "true",
+		},
+	}, nil
+}
+
+func (s *fileSink) Write(entry []byte) error {
+	line := make([]byte, 0, len(entry)+1)
+	line = append(line, entry...)
+	line = append(line, '\n')
+
+	_, err := s.logger.Write(line)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	return s.logger.Close()
+}
+
+// webhookSink POSTs each audit entry as a JSON document to an HTTP endpoint.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookSink(cfg map[string]string) (*webhookSink, error) {
+	url := cfg["webhook_url"]
+	if url == "" {
+		return nil, fmt.Errorf("webhook_url is required")
+	}
+
+	timeout := defaultWebhookTimeout
+	if v, ok := cfg["webhook_timeout"]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid webhook_timeout %q: must be a positive duration", v)
+		}
+		timeout = d
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if token := cfg["webhook_auth_token"]; token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	return &webhookSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (s *webhookSink) Write(entry []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(entry))
+	if err != nil {
+		return err
+	}
+
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// asyncSink decouples request handling from sink I/O using a bounded buffer.
+// When the buffer is full, entries are dropped and counted rather than blocking the caller.
+type asyncSink struct {
+	next      auditSink
+	entries   chan []byte
+	done      chan struct{}
+	dropped   atomic.Uint64
+	abandoned atomic.Bool
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newAsyncSink(next auditSink, bufferSize int) *asyncSink {
+	s := &asyncSink{
+		next:    next,
+		entries: make(chan []byte, bufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s
+}
+
+// run writes buffered entries until the sink is closed, then closes the underlying sink. Only run touches
+// next, so it's never closed while a write is in progress.
+func (s *asyncSink) run() {
+	defer close(s.done)
+
+	discarded := 0
+	for entry := range s.entries {
+		if s.abandoned.Load() {
+			discarded++
+			continue
+		}
+
+		if err := s.next.Write(entry); err != nil {
+			log.Printf("[ERROR] Tool audit sink write failed: %v", err)
+		}
+	}
+
+	if dropped := s.dropped.Load(); dropped > 0 {
+		log.Printf("[WARN] Tool audit sink dropped %d entries due to a full buffer", dropped)
+	}
+	if discarded > 0 {
+		log.Printf("[WARN] Tool audit sink discarded %d buffered entries that could not be flushed in time", discarded)
+	}
+
+	s.closeErr = s.next.Close()
+}
+
+func (s *asyncSink) Write(entry []byte) error {
+	select {
+	case s.entries <- entry:
+		return nil
+	default:
+		dropped := s.dropped.Add(1)
+		return fmt.Errorf("audit buffer full, %d entries dropped so far", dropped)
+	}
+}
+
+// Close stops accepting entries and waits for the buffered ones to be written and the underlying sink closed.
+// If ctx ends first, the remaining entries are discarded and the sink is closed once any write in progress
+// finishes, without waiting for it.
+func (s *asyncSink) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		close(s.entries)
+	})
+
+	select {
+	case <-s.done:
+		return s.closeErr
+	case <-ctx.Done():
+		s.abandoned.Store(true)
+		return fmt.Errorf("audit sink not flushed: %w", ctx.Err())
+	}
+}
+
+// positiveIntOrDefault parses an optional positive integer from the custom configuration.
+func positiveIntOrDefault(cfg map[string]string, key string, def int) (int, error) {
+	v, ok := cfg[key]
+	if !ok {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive integer", key, v)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,36 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"log/syslog"
+)
+
+// syslogSink forwards audit entries to a local or remote syslog daemon.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// newSyslogSink connects to syslog using syslog_network and syslog_address.
+// When both are empty the local syslog daemon is used.
+func newSyslogSink(cfg map[string]string) (*syslogSink, error) {
+	tag := cfg["syslog_tag"]
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+
+	w, err := syslog.Dial(cfg["syslog_network"], cfg["syslog_address"], syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &syslogSink{writer: w}, nil
+}
+
+func (s *syslogSink) Write(entry []byte) error {
+	return s.writer.Info(string(entry))
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
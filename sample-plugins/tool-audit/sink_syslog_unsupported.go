@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// newSyslogSink reports that syslog is unavailable, since log/syslog is not implemented on this platform.
+func newSyslogSink(_ map[string]string) (auditSink, error) {
+	return nil, fmt.Errorf("syslog sink is not supported on %s", runtime.GOOS)
+}